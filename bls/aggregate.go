@@ -0,0 +1,89 @@
+package bls
+
+import (
+	"errors"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// ErrDuplicateMessage is returned by AggregateVerify when two of the messages
+// are equal: the aggregation of signatures on distinct messages is only
+// secure if the messages are distinct, otherwise rogue public keys can forge
+// aggregates.
+var ErrDuplicateMessage = errors.New("bls: duplicate message in aggregate")
+
+// Aggregate adds up signatures into a single signature of G1, which
+// AggregateVerify checks against all the public keys and messages at once.
+// The signatures equal to the identity element are rejected.
+func Aggregate(s PairingSuite, sigs ...[]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("bls: no signature to aggregate")
+	}
+	agg := s.G1().Point().Null()
+	sig := s.G1().Point()
+	defer pbc.Release(s.G1(), agg, sig)
+	for _, buff := range sigs {
+		if err := pbc.UnmarshalNonIdentity(sig, buff); err != nil {
+			return nil, err
+		}
+		agg.Add(agg, sig)
+	}
+	return agg.MarshalBinary()
+}
+
+// AggregateVerify checks the aggregate sig of the signatures of the distinct
+// messages msgs[i] by the public keys publics[i], i.e. that
+//
+//	e(sig, G2) == prod_i e(H(msgs[i]), publics[i])
+//
+// which is computed as a single product of n + 1 pairings sharing the final
+// exponentiation. Duplicate messages are rejected with ErrDuplicateMessage,
+// as are the public keys and signatures equal to the identity element.
+func AggregateVerify(s PairingSuite, cs Ciphersuite, publics []abstract.Point, msgs [][]byte, sig []byte) error {
+	if len(publics) != len(msgs) {
+		return errors.New("bls: different numbers of public keys and messages")
+	}
+	if len(msgs) == 0 {
+		return errors.New("bls: empty aggregate")
+	}
+	seen := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		if seen[string(msg)] {
+			return ErrDuplicateMessage
+		}
+		seen[string(msg)] = true
+	}
+	null := s.G2().Point().Null()
+	defer pbc.Release(s.G2(), null)
+	for _, public := range publics {
+		if public.Equal(null) {
+			return errors.New("bls: invalid public key")
+		}
+	}
+	sigPoint := s.G1().Point()
+	if err := pbc.UnmarshalNonIdentity(sigPoint, sig); err != nil {
+		return err
+	}
+	negG2 := s.G2().Point().Base()
+	negG2.Neg(negG2)
+	p1s := []abstract.Point{sigPoint}
+	p2s := []abstract.Point{negG2}
+	for i, msg := range msgs {
+		p1s = append(p1s, hashed(s, cs, msg))
+		p2s = append(p2s, publics[i])
+	}
+	res := s.GT().PointGT().PairingProduct(p1s, p2s)
+	one := s.GT().Point().Null()
+	defer func() {
+		for _, p := range p1s {
+			pbc.Release(s.G1(), p)
+		}
+		pbc.Release(s.G2(), negG2)
+		pbc.Release(s.GT(), res, one)
+	}()
+	if !res.Equal(one) {
+		return errors.New("bls: invalid aggregate signature")
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// batchBits is the length of the random coefficients of the batch
+// verifications: a batch with an invalid signature passes with probability
+// 2^-batchBits.
+const batchBits = 128
+
+// BatchError is returned by the batch verifications with the positions of
+// the invalid signatures in the batch, in increasing order.
+type BatchError struct {
+	Invalid []int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("bls: %d invalid signatures in batch at %v", len(e.Invalid), e.Invalid)
+}
+
+// BatchVerify checks the signatures sigs[i] of msgs[i] by publics[i] at once.
+// It draws random coefficients r_i and checks that
+//
+//	e(sum_i r_i sigs[i], G2) == prod_m e(H(m), sum_{i: msgs[i] = m} r_i publics[i])
+//
+// with a single product of pairings, one per distinct message. If the check
+// fails, the batch is bisected until the invalid signatures are found, which
+// are returned in a *BatchError.
+func BatchVerify(s PairingSuite, cs Ciphersuite, publics []abstract.Point, msgs [][]byte, sigs [][]byte) error {
+	if len(publics) != len(msgs) || len(msgs) != len(sigs) {
+		return errors.New("bls: batch of different numbers of public keys, messages and signatures")
+	}
+	if len(sigs) == 0 {
+		return errors.New("bls: empty batch")
+	}
+	null := s.G2().Point().Null()
+	hashes := make(map[string]abstract.Point)
+	points := make([]abstract.Point, len(sigs))
+	defer func() {
+		for _, p := range points {
+			if p != nil {
+				pbc.Release(s.G1(), p)
+			}
+		}
+		for _, hm := range hashes {
+			pbc.Release(s.G1(), hm)
+		}
+		pbc.Release(s.G2(), null)
+	}()
+	var invalid, candidates []int
+	for i := range sigs {
+		p := s.G1().Point()
+		if publics[i].Equal(null) || pbc.UnmarshalNonIdentity(p, sigs[i]) != nil {
+			pbc.Release(s.G1(), p)
+			invalid = append(invalid, i)
+			continue
+		}
+		points[i] = p
+		if _, ok := hashes[string(msgs[i])]; !ok {
+			hashes[string(msgs[i])] = hashed(s, cs, msgs[i])
+		}
+		candidates = append(candidates, i)
+	}
+	check := func(indices []int) bool {
+		coeffs := batchCoefficients(s, indices)
+		// the public keys of a same message are combined first
+		var order []string
+		groups := make(map[string][]int)
+		groupCoeffs := make(map[string][]abstract.Scalar)
+		for k, i := range indices {
+			m := string(msgs[i])
+			if _, ok := groups[m]; !ok {
+				order = append(order, m)
+			}
+			groups[m] = append(groups[m], i)
+			groupCoeffs[m] = append(groupCoeffs[m], coeffs[k])
+		}
+		p1s := []abstract.Point{sumOf(s.G1(), points, indices, coeffs)}
+		var p2s []abstract.Point
+		for _, m := range order {
+			p1s = append(p1s, hashes[m])
+			p2s = append(p2s, sumOf(s.G2(), publics, groups[m], groupCoeffs[m]))
+		}
+		defer pbc.Release(s.G1(), p1s[0])
+		defer func() {
+			for _, p := range p2s {
+				pbc.Release(s.G2(), p)
+			}
+		}()
+		return pairingsVanish(s, p1s, p2s)
+	}
+	return batchResult(merge(invalid, bisect(candidates, check)))
+}
+
+// ThresholdBatchVerify checks the threshold signatures sigs of msg against
+// the public polynomial at once, like BatchVerify. Since all the public
+// shares come from the same polynomial, sum_i r_i X_i is computed as a single
+// multi-scalar multiplication of its commitments, and the check takes two
+// pairings whatever the number of signatures.
+func ThresholdBatchVerify(s PairingSuite, cs Ciphersuite, public *share.PubPoly, msg []byte, sigs []*ThresholdSig) error {
+	if len(sigs) == 0 {
+		return errors.New("bls: empty batch")
+	}
+	HM := hashed(s, cs, msg)
+	defer pbc.Release(s.G1(), HM)
+	return batchResult(thresholdBatchVerify(s, public, HM, sigs))
+}
+
+// thresholdBatchVerify returns the positions of the invalid signatures among
+// sigs, with the hash of the message HM.
+func thresholdBatchVerify(s PairingSuite, public *share.PubPoly, HM abstract.Point, sigs []*ThresholdSig) []int {
+	var invalid, candidates []int
+	points := make([]abstract.Point, len(sigs))
+	for i, sig := range sigs {
+		if sig == nil || sig.Sig == nil || sig.Index < 0 {
+			invalid = append(invalid, i)
+			continue
+		}
+		points[i] = sig.Sig
+		candidates = append(candidates, i)
+	}
+	_, commits := public.Info()
+	check := func(indices []int) bool {
+		// sum_i r_i X_i = sum_j (sum_i r_i x_i^j) C_j
+		coeffs := batchCoefficients(s, indices)
+		exps := make([]abstract.Scalar, len(commits))
+		for j := range exps {
+			exps[j] = s.G2().Scalar().Zero()
+		}
+		xi, pow := s.G2().Scalar(), s.G2().Scalar()
+		for k, i := range indices {
+			xi.SetInt64(1 + int64(sigs[i].Index))
+			pow.Set(coeffs[k])
+			for j := range exps {
+				exps[j].Add(exps[j], pow)
+				pow.Mul(pow, xi)
+			}
+		}
+		sum := sumOf(s.G1(), points, indices, coeffs)
+		key := pbc.MultiMul(s.G2(), commits, exps)
+		defer pbc.Release(s.G1(), sum)
+		defer pbc.Release(s.G2(), key)
+		return pairingsVanish(s, []abstract.Point{sum, HM}, []abstract.Point{key})
+	}
+	return merge(invalid, bisect(candidates, check))
+}
+
+// bisect returns the indices for which check fails: if it fails for all of
+// them together, they are split in halves which are checked separately.
+func bisect(indices []int, check func([]int) bool) []int {
+	if len(indices) == 0 || check(indices) {
+		return nil
+	}
+	if len(indices) == 1 {
+		return indices
+	}
+	half := len(indices) / 2
+	return append(bisect(indices[:half], check), bisect(indices[half:], check)...)
+}
+
+// batchCoefficients returns fresh random coefficients of batchBits bits, one
+// per index.
+func batchCoefficients(s PairingSuite, indices []int) []abstract.Scalar {
+	coeffs := make([]abstract.Scalar, len(indices))
+	for k := range coeffs {
+		coeffs[k] = s.G2().Scalar().SetBytes(random.Bytes(batchBits/8, random.Stream))
+	}
+	return coeffs
+}
+
+// sumOf returns sum_k coeffs[k] points[indices[k]].
+func sumOf(g abstract.Group, points []abstract.Point, indices []int, coeffs []abstract.Scalar) abstract.Point {
+	selected := make([]abstract.Point, len(indices))
+	for k, i := range indices {
+		selected[k] = points[i]
+	}
+	return pbc.MultiMul(g, selected, coeffs)
+}
+
+// pairingsVanish returns true if e(p1s[0], -G2) * prod_i e(p1s[i+1], p2s[i])
+// is the identity.
+func pairingsVanish(s PairingSuite, p1s, p2s []abstract.Point) bool {
+	negG2 := s.G2().Point().Base()
+	negG2.Neg(negG2)
+	res := s.GT().PointGT().PairingProduct(p1s, append([]abstract.Point{negG2}, p2s...))
+	one := s.GT().Point().Null()
+	defer pbc.Release(s.G2(), negG2)
+	defer pbc.Release(s.GT(), res, one)
+	return res.Equal(one)
+}
+
+// merge returns the union of the sorted slices a and b.
+func merge(a, b []int) []int {
+	res := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			res, a = append(res, a[0]), a[1:]
+		} else {
+			res, b = append(res, b[0]), b[1:]
+		}
+	}
+	return append(append(res, a...), b...)
+}
+
+// batchResult returns the *BatchError of the invalid positions, if any.
+func batchResult(invalid []int) error {
+	if len(invalid) > 0 {
+		return &BatchError{Invalid: invalid}
+	}
+	return nil
+}
@@ -12,22 +12,46 @@ type PairingSuite interface {
 	G1() abstract.Suite
 	G2() abstract.Suite
 	GT() pbc.PairingGroup
+	Curve() int
+}
+
+// Ciphersuite is the domain separation tag used to hash messages to G1. Each
+// application should use its own ciphersuite so signatures produced for one
+// of them can not be replayed in another one.
+type Ciphersuite string
+
+// HashToG1Suite returns the identifier of the hash to G1 of curve, which
+// ciphersuites embed after the conventions of the BLS signature draft.
+// BLS12-381 hashes to G1 with the SSWU suite of RFC 9380 and the curves of
+// mcl with the SVDW one.
+func HashToG1Suite(curve int) string {
+	if curve == pbc.CurveBLS12_381 {
+		return "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	}
+	return "PBCG1_XMD:SHA-256_SVDW_RO_"
+}
+
+// DefaultCiphersuite returns the ciphersuite of the plain BLS signatures on
+// the curve of s.
+func DefaultCiphersuite(s PairingSuite) Ciphersuite {
+	return Ciphersuite("BLS_SIG_" + HashToG1Suite(s.Curve()) + "NUL_")
 }
 
 func NewKeyPair(s PairingSuite, r cipher.Stream) (abstract.Scalar, abstract.Point) {
 	sk := s.G2().Scalar().Pick(r)
-	pk := s.G2().Point().Mul(nil, sk)
-	return sk, pk
+	pk := secretPoint(s.G2().Point()).Mul(nil, sk)
+	return sk, publicPoint(pk)
 }
 
 // Performs a BLS signature operation. Namely, it computes:
 //
 //   x * H(m) as a point on G1
 //
-// where x is the private key, and m the message.
-func Sign(s PairingSuite, private abstract.Scalar, msg []byte) []byte {
-	HM := hashed(s, msg)
-	xHM := HM.Mul(HM, private)
+// where x is the private key, m the message and H the hash to G1 of the
+// ciphersuite cs.
+func Sign(s PairingSuite, cs Ciphersuite, private abstract.Scalar, msg []byte) []byte {
+	HM := hashed(s, cs, msg)
+	xHM := secretPoint(HM).Mul(HM, private)
 	sig, _ := xHM.MarshalBinary()
 	return sig
 }
@@ -37,26 +61,54 @@ func Sign(s PairingSuite, private abstract.Scalar, msg []byte) []byte {
 //  e(H(m),X) == e(H(m), G2^x) == e(H(m)^x, G2) == e(s, G2)
 //
 // where m is the message, X the public key from G2, s the signature and G2 the base
-// point from which the public key have been generated.
-func Verify(s PairingSuite, public abstract.Point, msg, sig []byte) error {
-	HM := hashed(s, msg)
-	left := s.GT().PointGT().Pairing(HM, public)
+// point from which the public key have been generated. Signatures and public
+// keys equal to the identity element are rejected. Both pairings are
+// computed at once by checking e(s, -G2) * e(H(m), X) == 1. The ciphersuite
+// cs must be the one used to sign.
+func Verify(s PairingSuite, cs Ciphersuite, public abstract.Point, msg, sig []byte) error {
+	if public.Equal(s.G2().Point().Null()) {
+		return errors.New("bls: invalid public key")
+	}
 	sigPoint := s.G1().Point()
-	if err := sigPoint.UnmarshalBinary(sig); err != nil {
+	if err := pbc.UnmarshalNonIdentity(sigPoint, sig); err != nil {
 		return err
 	}
-
-	g2 := s.G2().Point().Base()
-	right := s.GT().PointGT().Pairing(sigPoint, g2)
-
-	if !left.Equal(right) {
+	HM := hashed(s, cs, msg)
+	if !verifyPairing(s, sigPoint, HM, public) {
 		return errors.New("bls: invalid signature")
 	}
 	return nil
 }
 
-func hashed(s PairingSuite, msg []byte) abstract.Point {
-	hashed := s.G1().Hash().Sum(msg)
-	p, _ := s.G1().Point().Pick(nil, s.G1().Cipher(hashed))
+// verifyPairing returns true if e(sig, G2) == e(HM, public).
+func verifyPairing(s PairingSuite, sig, HM, public abstract.Point) bool {
+	return pairingsVanish(s, []abstract.Point{sig, HM}, []abstract.Point{public})
+}
+
+// hashed returns the hash of msg to G1 with the ciphersuite as domain
+// separation tag.
+func hashed(s PairingSuite, cs Ciphersuite, msg []byte) abstract.Point {
+	if cs == "" {
+		panic("bls: empty ciphersuite")
+	}
+	return s.G1().(pbc.PointHasher).HashToPoint(msg, []byte(cs))
+}
+
+// secretPoint switches p to constant time multiplications, for the
+// multiplications by private keys and shares.
+func secretPoint(p abstract.Point) abstract.Point {
+	if v, ok := p.(pbc.VarTimer); ok {
+		v.SetVarTime(false)
+	}
+	return p
+}
+
+// publicPoint switches p back to variable time multiplications once it only
+// holds a public value, so later multi-scalar multiplications on it are not
+// computed term by term.
+func publicPoint(p abstract.Point) abstract.Point {
+	if v, ok := p.(pbc.VarTimer); ok {
+		v.SetVarTime(true)
+	}
 	return p
 }
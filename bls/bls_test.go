@@ -1,9 +1,12 @@
 package bls
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/dedis/paper_17_dfinity/pbc"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/abstract"
 
 	"github.com/dedis/kyber/random"
 )
@@ -12,9 +15,226 @@ func TestBLSSig(t *testing.T) {
 	sk, pk := NewKeyPair(pairing, random.Stream)
 	msg := []byte("hello world")
 
-	sig := Sign(pairing, sk, msg)
-	require.Nil(t, Verify(pairing, pk, msg, sig))
+	sig := Sign(pairing, DefaultCiphersuite(pairing), sk, msg)
+	require.Nil(t, Verify(pairing, DefaultCiphersuite(pairing), pk, msg, sig))
 
 	wrongMsg := []byte("evil message")
-	require.Error(t, Verify(pairing, pk, msg, wrongMsg))
+	require.Error(t, Verify(pairing, DefaultCiphersuite(pairing), pk, msg, wrongMsg))
+}
+
+func TestBLSIdentity(t *testing.T) {
+	_, pk := NewKeyPair(pairing, random.Stream)
+	msg := []byte("hello world")
+
+	null, _ := pairing.G1().Point().Null().MarshalBinary()
+	require.Error(t, Verify(pairing, DefaultCiphersuite(pairing), pk, msg, null))
+
+	sk := pairing.G2().Scalar().Zero()
+	sig := Sign(pairing, DefaultCiphersuite(pairing), sk, msg)
+	require.Error(t, Verify(pairing, DefaultCiphersuite(pairing), pairing.G2().Point().Null(), msg, sig))
+}
+
+func TestBLSCiphersuite(t *testing.T) {
+	sk, pk := NewKeyPair(pairing, random.Stream)
+	msg := []byte("hello world")
+
+	sig := Sign(pairing, "APP_A_", sk, msg)
+	require.Nil(t, Verify(pairing, "APP_A_", pk, msg, sig))
+	require.Error(t, Verify(pairing, "APP_B_", pk, msg, sig))
+	require.Panics(t, func() { Sign(pairing, "", sk, msg) })
+
+	h2c := HashToG1Suite(pairing.Curve())
+	require.Equal(t, Ciphersuite("BLS_SIG_"+h2c+"NUL_"), DefaultCiphersuite(pairing))
+	require.Equal(t, Ciphersuite("BLS_POP_"+h2c+"POP_"), PopCiphersuite(pairing))
+	require.Equal(t, "BLS12381G1_XMD:SHA-256_SSWU_RO_", HashToG1Suite(pbc.CurveBLS12_381))
+	require.Equal(t, "PBCG1_XMD:SHA-256_SVDW_RO_", HashToG1Suite(pbc.CurveFp254BNb))
+}
+
+func TestAggregateVerify(t *testing.T) {
+	n := 5
+	publics := make([]abstract.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range publics {
+		var sk abstract.Scalar
+		sk, publics[i] = NewKeyPair(pairing, random.Stream)
+		msgs[i] = []byte(fmt.Sprintf("block %d", i))
+		sigs[i] = Sign(pairing, DefaultCiphersuite(pairing), sk, msgs[i])
+	}
+	agg, err := Aggregate(pairing, sigs...)
+	require.Nil(t, err)
+	require.Len(t, agg, len(sigs[0]))
+	require.Nil(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, agg))
+
+	// a single signature is an aggregate of one
+	require.Nil(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics[:1], msgs[:1], sigs[0]))
+
+	// a missing signature, a swapped message or another ciphersuite
+	partial, err := Aggregate(pairing, sigs[1:]...)
+	require.Nil(t, err)
+	require.Error(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, partial))
+	swapped := append([][]byte{msgs[1], msgs[0]}, msgs[2:]...)
+	require.Error(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics, swapped, agg))
+	require.Error(t, AggregateVerify(pairing, "APP_A_", publics, msgs, agg))
+
+	dup := append([][]byte{msgs[1]}, msgs[1:]...)
+	require.Equal(t, ErrDuplicateMessage, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics, dup, agg))
+	require.Error(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics[1:], msgs, agg))
+
+	null, _ := pairing.G1().Point().Null().MarshalBinary()
+	_, err = Aggregate(pairing, sigs[0], null)
+	require.Error(t, err)
+	require.Error(t, AggregateVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, null))
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	n := 4
+	msg := []byte("block 7")
+	registry := NewKeyRegistry(pairing)
+	publics := make([]abstract.Point, n)
+	sigs := make([][]byte, n)
+	for i := range publics {
+		var sk abstract.Scalar
+		sk, publics[i] = NewKeyPair(pairing, random.Stream)
+		proof := PopProve(pairing, sk)
+		require.Nil(t, PopVerify(pairing, publics[i], proof))
+		require.Nil(t, registry.Register(publics[i], proof))
+		sigs[i] = Sign(pairing, DefaultCiphersuite(pairing), sk, msg)
+
+		// a proof is not a signature of the public key and vice versa
+		buff, _ := publics[i].MarshalBinary()
+		require.Error(t, Verify(pairing, DefaultCiphersuite(pairing), publics[i], buff, proof))
+		require.Error(t, PopVerify(pairing, publics[i], Sign(pairing, DefaultCiphersuite(pairing), sk, buff)))
+	}
+	require.Equal(t, n, registry.Len())
+	agg, err := Aggregate(pairing, sigs...)
+	require.Nil(t, err)
+	require.Nil(t, FastAggregateVerify(pairing, DefaultCiphersuite(pairing), publics, msg, agg))
+	require.Nil(t, registry.FastAggregateVerify(DefaultCiphersuite(pairing), publics, msg, agg))
+	require.Error(t, FastAggregateVerify(pairing, DefaultCiphersuite(pairing), publics[1:], msg, agg))
+	require.Error(t, FastAggregateVerify(pairing, DefaultCiphersuite(pairing), publics, []byte("block 8"), agg))
+	require.Error(t, FastAggregateVerify(pairing, DefaultCiphersuite(pairing), nil, msg, agg))
+
+	// a rogue key cancelling the others out can not prove its possession
+	sk, pk := NewKeyPair(pairing, random.Stream)
+	rogue := pairing.G2().Point().Add(pairing.G2().Point().Null(), pk)
+	for _, p := range publics {
+		rogue.Sub(rogue, p)
+	}
+	forged := Sign(pairing, DefaultCiphersuite(pairing), sk, msg)
+	rogues := append([]abstract.Point{rogue}, publics...)
+	require.Nil(t, FastAggregateVerify(pairing, DefaultCiphersuite(pairing), rogues, msg, forged))
+	require.Equal(t, ErrUnknownKey, registry.FastAggregateVerify(DefaultCiphersuite(pairing), rogues, msg, forged))
+	require.Error(t, registry.Register(rogue, PopProve(pairing, sk)))
+	require.False(t, registry.Contains(rogue))
+
+	// proofs and registered keys do not depend on the point format
+	uncompressed := pbc.NewPairing(pairing.Curve())
+	uncompressed.SetPointFormat(pbc.Uncompressed)
+	upk := uncompressed.G2().Point().Mul(nil, sk)
+	require.True(t, upk.Equal(pk))
+	proof := PopProve(uncompressed, sk)
+	require.Nil(t, PopVerify(pairing, pk, proof))
+	require.Nil(t, registry.Register(upk, proof))
+	require.True(t, registry.Contains(pk))
+	require.Equal(t, n+1, registry.Len())
+}
+
+func TestBitmap(t *testing.T) {
+	b, err := EncodeBitmap(11, []int{0, 3, 10})
+	require.Nil(t, err)
+	require.Equal(t, Bitmap{0x09, 0x04}, b)
+	require.Equal(t, 3, b.Count())
+	require.Equal(t, []int{0, 3, 10}, b.Indices())
+	require.True(t, b.IsSet(10))
+	require.False(t, b.IsSet(11))
+
+	_, err = EncodeBitmap(11, []int{11})
+	require.Error(t, err)
+	d, err := DecodeBitmap(b, 11)
+	require.Nil(t, err)
+	require.Equal(t, b, d)
+	_, err = DecodeBitmap(b, 17)
+	require.Equal(t, ErrInvalidBitmap, err)
+	_, err = DecodeBitmap([]byte{0x09, 0x08}, 11)
+	require.Equal(t, ErrInvalidBitmap, err)
+}
+
+func TestMultiSig(t *testing.T) {
+	n := 10
+	msg := []byte("block 7")
+	registry := NewKeyRegistry(pairing)
+	sigs := make(map[int][]byte)
+	for i := 0; i < n; i++ {
+		sk, pk := NewKeyPair(pairing, random.Stream)
+		require.Nil(t, registry.Register(pk, PopProve(pairing, sk)))
+		if i%3 != 1 {
+			sigs[i] = Sign(pairing, DefaultCiphersuite(pairing), sk, msg)
+		}
+		if i == 0 {
+			// registering a key again does not change the roster
+			require.Nil(t, registry.Register(pk, PopProve(pairing, sk)))
+		}
+	}
+	require.Equal(t, n, registry.Len())
+	m, err := AggregateMultiSig(pairing, n, sigs)
+	require.Nil(t, err)
+	require.Equal(t, []int{0, 2, 3, 5, 6, 8, 9}, m.Signers.Indices())
+	require.Nil(t, VerifyMultiSig(registry, DefaultCiphersuite(pairing), msg, m, 7))
+	require.Equal(t, ErrNotEnoughSigners, VerifyMultiSig(registry, DefaultCiphersuite(pairing), msg, m, 8))
+	require.Error(t, VerifyMultiSig(registry, DefaultCiphersuite(pairing), []byte("block 8"), m, 0))
+	require.Equal(t, ErrInvalidBitmap, VerifyMultiSig(NewKeyRegistry(pairing), DefaultCiphersuite(pairing), msg, m, 0))
+
+	// claiming a signer which did not sign
+	forged := &MultiSig{Signers: append(Bitmap(nil), m.Signers...), Sig: m.Sig}
+	forged.Signers.Set(1)
+	require.Error(t, VerifyMultiSig(registry, DefaultCiphersuite(pairing), msg, forged, 0))
+
+	buff, err := m.MarshalBinary()
+	require.Nil(t, err)
+	dec, err := UnmarshalMultiSig(pairing, n, buff)
+	require.Nil(t, err)
+	require.Equal(t, m, dec)
+	_, err = UnmarshalMultiSig(pairing, n+8, buff)
+	require.Error(t, err)
+
+	_, err = AggregateMultiSig(pairing, n, map[int][]byte{n: sigs[0]})
+	require.Error(t, err)
+}
+
+func TestBatchVerify(t *testing.T) {
+	n := 9
+	publics := make([]abstract.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range publics {
+		var sk abstract.Scalar
+		sk, publics[i] = NewKeyPair(pairing, random.Stream)
+		// some signers share a message
+		msgs[i] = []byte(fmt.Sprintf("block %d", i%4))
+		sigs[i] = Sign(pairing, DefaultCiphersuite(pairing), sk, msgs[i])
+	}
+	require.Nil(t, BatchVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, sigs))
+
+	null, _ := pairing.G1().Point().Null().MarshalBinary()
+	bad := append([][]byte(nil), sigs...)
+	bad[2], bad[3] = sigs[3], sigs[2]
+	bad[7] = null
+	bad[8] = sigs[8][1:]
+	err := BatchVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, bad)
+	require.Equal(t, &BatchError{Invalid: []int{2, 3, 7, 8}}, err)
+
+	// two invalid signatures which cancel out in a plain sum are caught
+	p := pairing.G1().Point().Base()
+	sig0, sig1 := pairing.G1().Point(), pairing.G1().Point()
+	require.Nil(t, sig0.UnmarshalBinary(sigs[0]))
+	require.Nil(t, sig1.UnmarshalBinary(sigs[1]))
+	bad = append([][]byte(nil), sigs...)
+	bad[0], _ = sig0.Add(sig0, p).MarshalBinary()
+	bad[1], _ = sig1.Sub(sig1, p).MarshalBinary()
+	err = BatchVerify(pairing, DefaultCiphersuite(pairing), publics, msgs, bad)
+	require.Equal(t, &BatchError{Invalid: []int{0, 1}}, err)
+
+	require.Error(t, BatchVerify(pairing, DefaultCiphersuite(pairing), publics, msgs[1:], sigs))
+	require.Error(t, BatchVerify(pairing, DefaultCiphersuite(pairing), nil, nil, nil))
 }
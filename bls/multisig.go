@@ -0,0 +1,161 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+)
+
+// Errors returned by the decoding and verification of the multisignatures.
+var (
+	ErrInvalidBitmap    = errors.New("bls: invalid signers bitmap")
+	ErrNotEnoughSigners = errors.New("bls: not enough signers")
+)
+
+// Bitmap is the set of the signers of a multisignature among a roster of n
+// public keys: the signer of index i is bit i % 8 of byte i / 8, the least
+// significant bit first. It takes (n + 7) / 8 bytes and its padding bits are
+// zero, so each set has a single encoding.
+type Bitmap []byte
+
+// NewBitmap returns the empty bitmap of a roster of n public keys.
+func NewBitmap(n int) Bitmap {
+	return make(Bitmap, (n+7)/8)
+}
+
+// EncodeBitmap returns the bitmap of a roster of n public keys where the
+// given indices are set.
+func EncodeBitmap(n int, indices []int) (Bitmap, error) {
+	b := NewBitmap(n)
+	for _, i := range indices {
+		if i < 0 || i >= n {
+			return nil, fmt.Errorf("bls: signer %d out of a roster of %d", i, n)
+		}
+		b.Set(i)
+	}
+	return b, nil
+}
+
+// DecodeBitmap checks that buff is the bitmap of a roster of n public keys
+// and returns a copy of it.
+func DecodeBitmap(buff []byte, n int) (Bitmap, error) {
+	if n < 0 || len(buff) != (n+7)/8 {
+		return nil, ErrInvalidBitmap
+	}
+	if n%8 != 0 && buff[len(buff)-1]>>uint(n%8) != 0 {
+		return nil, ErrInvalidBitmap
+	}
+	return append(Bitmap(nil), buff...), nil
+}
+
+// Set adds the signer of index i.
+func (b Bitmap) Set(i int) {
+	b[i/8] |= 1 << uint(i%8)
+}
+
+// IsSet returns true if the signer of index i is in the bitmap.
+func (b Bitmap) IsSet(i int) bool {
+	if i < 0 || i/8 >= len(b) {
+		return false
+	}
+	return b[i/8]&(1<<uint(i%8)) != 0
+}
+
+// Count returns the number of signers.
+func (b Bitmap) Count() int {
+	c := 0
+	for _, x := range b {
+		for ; x != 0; x &= x - 1 {
+			c++
+		}
+	}
+	return c
+}
+
+// Indices returns the indices of the signers in increasing order.
+func (b Bitmap) Indices() []int {
+	var indices []int
+	for i := 0; i < 8*len(b); i++ {
+		if b.IsSet(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// MultiSig is an accountable subgroup multisignature: the aggregate of the
+// signatures of a message by some members of a fixed roster of public keys,
+// and the bitmap of which members signed it.
+type MultiSig struct {
+	Signers Bitmap
+	Sig     []byte
+}
+
+// AggregateMultiSig returns the multisignature of the signatures sigs[i] by
+// the members i of a roster of n public keys.
+func AggregateMultiSig(s PairingSuite, n int, sigs map[int][]byte) (*MultiSig, error) {
+	indices := make([]int, 0, len(sigs))
+	for i := range sigs {
+		indices = append(indices, i)
+	}
+	signers, err := EncodeBitmap(n, indices)
+	if err != nil {
+		return nil, err
+	}
+	all := make([][]byte, 0, len(sigs))
+	for _, i := range signers.Indices() {
+		all = append(all, sigs[i])
+	}
+	sig, err := Aggregate(s, all...)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSig{Signers: signers, Sig: sig}, nil
+}
+
+// MarshalBinary returns the bitmap followed by the aggregate signature.
+func (m *MultiSig) MarshalBinary() ([]byte, error) {
+	return append(append([]byte(nil), m.Signers...), m.Sig...), nil
+}
+
+// UnmarshalMultiSig decodes the multisignature of a roster of n public keys
+// encoded by MarshalBinary.
+func UnmarshalMultiSig(s PairingSuite, n int, buff []byte) (*MultiSig, error) {
+	l := (n + 7) / 8
+	if n < 0 || len(buff) != l+s.G1().PointLen() {
+		return nil, errors.New("bls: invalid multisignature length")
+	}
+	signers, err := DecodeBitmap(buff[:l], n)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSig{Signers: signers, Sig: append([]byte(nil), buff[l:]...)}, nil
+}
+
+// VerifyMultiSig checks the multisignature m of msg by the public keys of the
+// registry set in its bitmap, of which there must be at least min: bit i
+// stands for the i-th registered key. The aggregate public key is rebuilt from
+// the bitmap and checked with two pairings, as in FastAggregateVerify, which
+// the proofs of possession checked by the registry make secure.
+func VerifyMultiSig(r *KeyRegistry, cs Ciphersuite, msg []byte, m *MultiSig, min int) error {
+	s, roster := r.s, r.roster()
+	signers, err := DecodeBitmap(m.Signers, len(roster))
+	if err != nil {
+		return err
+	}
+	count := signers.Count()
+	if count == 0 || count < min {
+		return ErrNotEnoughSigners
+	}
+	null := s.G2().Point().Null()
+	agg := s.G2().Point().Null()
+	defer pbc.Release(s.G2(), null, agg)
+	for _, i := range signers.Indices() {
+		if roster[i].Equal(null) {
+			return errors.New("bls: invalid public key")
+		}
+		agg.Add(agg, roster[i])
+	}
+	return Verify(s, cs, agg, msg, m.Sig)
+}
@@ -0,0 +1,148 @@
+package bls
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// PopCiphersuite returns the domain separation tag of the proofs of
+// possession on the curve of s. It differs from the ones of the signatures so
+// a proof can never be used as a signature of the encoding of a public key,
+// nor the other way round.
+func PopCiphersuite(s PairingSuite) Ciphersuite {
+	return Ciphersuite("BLS_POP_" + HashToG1Suite(s.Curve()) + "POP_")
+}
+
+// ErrUnknownKey is returned by KeyRegistry.FastAggregateVerify for a public
+// key which was not registered with a valid proof of possession.
+var ErrUnknownKey = errors.New("bls: public key without proof of possession")
+
+// PopProve returns the proof of possession of the private key, the signature
+// of the compressed encoding of its public key under PopCiphersuite(s). It protects the
+// multisignatures checked by FastAggregateVerify against rogue public keys,
+// computed from the public keys of others to cancel them out of the sum.
+func PopProve(s PairingSuite, private abstract.Scalar) []byte {
+	public := secretPoint(s.G2().Point()).Mul(nil, private)
+	defer pbc.Release(s.G2(), public)
+	buff, _ := keyEncoding(public)
+	return Sign(s, PopCiphersuite(s), private, buff)
+}
+
+// PopVerify checks the proof of possession of the private key of public.
+func PopVerify(s PairingSuite, public abstract.Point, proof []byte) error {
+	buff, err := keyEncoding(public)
+	if err != nil {
+		return err
+	}
+	if err := Verify(s, PopCiphersuite(s), public, buff, proof); err != nil {
+		return errors.New("bls: invalid proof of possession")
+	}
+	return nil
+}
+
+// keyEncoding returns the compressed encoding of public, which proofs of
+// possession sign and KeyRegistry indexes, so neither depends on the point
+// format of the suite.
+func keyEncoding(public abstract.Point) ([]byte, error) {
+	if m, ok := public.(pbc.FormatMarshaler); ok {
+		return m.MarshalFormat(pbc.Compressed)
+	}
+	return public.MarshalBinary()
+}
+
+// FastAggregateVerify checks the aggregate sig of the signatures of the same
+// message msg by all the public keys, as computed by Aggregate, with two
+// pairings against the sum of the public keys. It is only secure if the
+// proofs of possession of all the public keys have been checked with
+// PopVerify beforehand, which KeyRegistry takes care of.
+func FastAggregateVerify(s PairingSuite, cs Ciphersuite, publics []abstract.Point, msg, sig []byte) error {
+	if len(publics) == 0 {
+		return errors.New("bls: no public key")
+	}
+	null := s.G2().Point().Null()
+	agg := s.G2().Point().Null()
+	defer pbc.Release(s.G2(), null, agg)
+	for _, public := range publics {
+		if public.Equal(null) {
+			return errors.New("bls: invalid public key")
+		}
+		agg.Add(agg, public)
+	}
+	return Verify(s, cs, agg, msg, sig)
+}
+
+// KeyRegistry is a set of public keys whose proofs of possession have been
+// checked, so the members of a committee can co-sign messages by adding up
+// their signatures without running a DKG. The keys are numbered in the order
+// of their registration, which makes the registry the roster of the
+// multisignatures checked by VerifyMultiSig. It is safe for concurrent use.
+type KeyRegistry struct {
+	s    PairingSuite
+	mu   sync.RWMutex
+	keys map[string]int
+	list []abstract.Point
+}
+
+// NewKeyRegistry returns an empty registry for the public keys of s.
+func NewKeyRegistry(s PairingSuite) *KeyRegistry {
+	return &KeyRegistry{s: s, keys: make(map[string]int)}
+}
+
+// Register adds public to the registry if proof is a valid proof of
+// possession of its private key. Registering a key twice keeps its first
+// index.
+func (r *KeyRegistry) Register(public abstract.Point, proof []byte) error {
+	if err := PopVerify(r.s, public, proof); err != nil {
+		return err
+	}
+	buff, _ := keyEncoding(public)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[string(buff)]; !ok {
+		r.keys[string(buff)] = len(r.list)
+		r.list = append(r.list, public)
+	}
+	return nil
+}
+
+// Contains returns true if public has been registered.
+func (r *KeyRegistry) Contains(public abstract.Point) bool {
+	buff, err := keyEncoding(public)
+	if err != nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.keys[string(buff)]
+	return ok
+}
+
+// Len returns the number of registered public keys.
+func (r *KeyRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.list)
+}
+
+// roster returns the registered public keys in the order of their
+// registration. Since keys are only appended, the slice is never modified
+// afterwards.
+func (r *KeyRegistry) roster() []abstract.Point {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.list
+}
+
+// FastAggregateVerify is FastAggregateVerify for registered public keys only:
+// it returns ErrUnknownKey if one of them has not been registered.
+func (r *KeyRegistry) FastAggregateVerify(cs Ciphersuite, publics []abstract.Point, msg, sig []byte) error {
+	for _, public := range publics {
+		if !r.Contains(public) {
+			return ErrUnknownKey
+		}
+	}
+	return FastAggregateVerify(r.s, cs, publics, msg, sig)
+}
@@ -3,6 +3,8 @@ package bls
 import (
 	"errors"
 
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"github.com/dedis/paper_17_dfinity/poly"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/share"
 )
@@ -20,54 +22,115 @@ type ThresholdSig struct {
 // ThresholdSign generates the regular BLS signature and also computes a
 // discrete log equality proof to show that the signature have been correctly
 // generated from the private share generated during a DKG.
-func ThresholdSign(s PairingSuite, d DistKeyShare, msg []byte) *ThresholdSig {
+func ThresholdSign(s PairingSuite, cs Ciphersuite, d DistKeyShare, msg []byte) *ThresholdSig {
 	// sig = H(m) * x_i in G1
-	HM := hashed(s, msg)
-	xHM := HM.Mul(HM, d.PriShare().V)
+	HM := hashed(s, cs, msg)
+	xHM := secretPoint(HM).Mul(HM, d.PriShare().V)
 
 	return &ThresholdSig{
 		Index: d.PriShare().I,
-		Sig:   xHM,
+		Sig:   publicPoint(xHM),
 	}
 
 }
 
 // ThresholdVerify verifies that the threshold signature is have been correctly
 // generated from the private share generated during a DKG.
-func ThresholdVerify(s PairingSuite, public *share.PubPoly, msg []byte, sig *ThresholdSig) bool {
-	HM := hashed(s, msg)
-	// e(H(m) * xi, G2)
-	eXHM := s.GT().PointGT().Pairing(sig.Sig, s.G2().Point().Base())
-	// e(G1, G2 * xi)
-	xiG := public.Eval(sig.Index).V
-	exiG := s.GT().PointGT().Pairing(HM, xiG)
+func ThresholdVerify(s PairingSuite, cs Ciphersuite, public *share.PubPoly, msg []byte, sig *ThresholdSig) bool {
+	return thresholdVerify(s, public, hashed(s, cs, msg), sig)
+}
+
+// thresholdVerify is ThresholdVerify with the hash of the message HM.
+func thresholdVerify(s PairingSuite, public *share.PubPoly, HM abstract.Point, sig *ThresholdSig) bool {
+	// e(H(m) * xi, G2) == e(H(m), G2 * xi)
+	xiG := pbc.EvalPubPoly(s.G2(), public, sig.Index).V
+	defer pbc.Release(s.G2(), xiG)
+	return verifyPairing(s, sig.Sig, HM, xiG)
+}
+
+// Errors of the recovery of threshold signatures.
+var (
+	ErrMalformedShare  = errors.New("bls: malformed threshold signature")
+	ErrInvalidShare    = errors.New("bls: invalid threshold signature")
+	ErrNotEnoughShares = errors.New("bls: not enough valid threshold signatures")
+)
 
-	return eXHM.Equal(exiG)
+// Blame reports a threshold signature rejected by
+// RobustAggregateSignatures, so the node which sent it can be dealt with.
+type Blame struct {
+	Index  int           // index of the share, -1 if Sig is nil
+	Sig    *ThresholdSig // the rejected signature
+	Reason error         // ErrMalformedShare or ErrInvalidShare
 }
 
-func AggregateSignatures(s PairingSuite, public *share.PubPoly, msg []byte, sigs []*ThresholdSig, n, t int) ([]byte, error) {
-	pubShares := make([]*share.PubShare, 0, n)
+// AggregateSignatures recovers the signature of msg by the distributed key
+// out of t valid threshold signatures among sigs, as
+// RobustAggregateSignatures does, without reporting the invalid ones.
+func AggregateSignatures(s PairingSuite, cs Ciphersuite, public *share.PubPoly, msg []byte, sigs []*ThresholdSig, n, t int) ([]byte, error) {
+	sig, _, err := RobustAggregateSignatures(s, cs, public, msg, sigs, n, t)
+	return sig, err
+}
+
+// RobustAggregateSignatures recovers the signature of msg by the distributed
+// key out of t valid threshold signatures among sigs, and reports the
+// signatures it rejected. It first recombines the first t distinct shares
+// without checking them, which costs a single verification of the result.
+// Only if it is invalid are all the shares checked, at once as by
+// ThresholdBatchVerify, and the recombination done again out of the valid
+// ones. Reed-Solomon decoding is not an option here since the shares are
+// points whose discrete logarithms are unknown. The shares which are not
+// used by the optimistic recombination are not checked, so the blames only
+// cover all the invalid shares when it fails.
+func RobustAggregateSignatures(s PairingSuite, cs Ciphersuite, public *share.PubPoly, msg []byte, sigs []*ThresholdSig, n, t int) ([]byte, []*Blame, error) {
+	HM := hashed(s, cs, msg)
+	defer pbc.Release(s.G1(), HM)
+	var blames []*Blame
+	candidates := make([]*ThresholdSig, 0, len(sigs))
 	for _, sig := range sigs {
-		if !ThresholdVerify(s, public, msg, sig) {
-			continue
-		}
-		pubShares = append(pubShares, &share.PubShare{V: sig.Sig, I: sig.Index})
-		if len(pubShares) >= t {
-			break
+		switch {
+		case sig == nil:
+			blames = append(blames, &Blame{Index: -1, Reason: ErrMalformedShare})
+		case sig.Sig == nil || sig.Index < 0 || sig.Index >= n:
+			blames = append(blames, &Blame{Index: sig.Index, Sig: sig, Reason: ErrMalformedShare})
+		default:
+			candidates = append(candidates, sig)
 		}
 	}
+	if buff := recoverSignature(s, public, HM, candidates, n, t); buff != nil {
+		return buff, blames, nil
+	}
 
-	if len(pubShares) < t {
-		return nil, errors.New("not enough valid threshold bls signatures")
+	invalid := thresholdBatchVerify(s, public, HM, candidates)
+	valid := make([]*ThresholdSig, 0, len(candidates))
+	for i, sig := range candidates {
+		if len(invalid) > 0 && invalid[0] == i {
+			invalid = invalid[1:]
+			blames = append(blames, &Blame{Index: sig.Index, Sig: sig, Reason: ErrInvalidShare})
+			continue
+		}
+		valid = append(valid, sig)
+	}
+	if buff := recoverSignature(s, public, HM, valid, n, t); buff != nil {
+		return buff, blames, nil
 	}
+	return nil, blames, ErrNotEnoughShares
+}
 
-	sig, err := share.RecoverCommit(s.G1(), pubShares, t, n)
+// recoverSignature recombines the first t distinct shares among sigs and
+// returns the encoding of the result if it is a valid signature, or nil.
+func recoverSignature(s PairingSuite, public *share.PubPoly, HM abstract.Point, sigs []*ThresholdSig, n, t int) []byte {
+	pubShares := make([]*share.PubShare, len(sigs))
+	for i, sig := range sigs {
+		pubShares[i] = &share.PubShare{V: sig.Sig, I: sig.Index}
+	}
+	sig, err := poly.RecoverCommit(s.G1(), pubShares, t, n)
 	if err != nil {
-		return nil, err
+		return nil
 	}
-	buff, _ := sig.MarshalBinary()
-	if err := Verify(s, public.Commit(), msg, buff); err != nil {
-		panic("math is wrong?")
+	defer pbc.Release(s.G1(), sig)
+	if !verifyPairing(s, sig, HM, public.Commit()) {
+		return nil
 	}
-	return buff, nil
+	buff, _ := sig.MarshalBinary()
+	return buff
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
 )
 
 var pairing = pbc.NewPairingFp254BNb()
@@ -45,21 +46,77 @@ func TestThresholdBLS(t *testing.T) {
 	require.Equal(t, xiG.String(), xiG2.String())
 
 	msg := []byte("Hello World")
-	tsig := ThresholdSign(pairing, dks, msg)
+	tsig := ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
 	require.Nil(t, err)
 
-	require.True(t, ThresholdVerify(pairing, dks.Polynomial(), msg, tsig))
+	require.True(t, ThresholdVerify(pairing, DefaultCiphersuite(pairing), dks.Polynomial(), msg, tsig))
 
 	sigs := make([]*ThresholdSig, nbParticipants)
 	for i, d := range dkgs {
 		dks, err := d.DistKeyShare()
 		require.Nil(t, err)
-		sigs[i] = ThresholdSign(pairing, dks, msg)
+		sigs[i] = ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
 	}
 	tt := nbParticipants/2 + 1
-	sig, err := AggregateSignatures(pairing, dks.Polynomial(), msg, sigs, nbParticipants, tt)
+	sig, err := AggregateSignatures(pairing, DefaultCiphersuite(pairing), dks.Polynomial(), msg, sigs, nbParticipants, tt)
 	require.Nil(t, err)
-	require.Nil(t, Verify(pairing, dks.Polynomial().Commit(), msg, sig))
+	require.Nil(t, Verify(pairing, DefaultCiphersuite(pairing), dks.Polynomial().Commit(), msg, sig))
+}
+
+// TestThresholdBLSCurves runs TestThresholdBLS on the other curves supported
+// by the backend.
+func TestThresholdBLSCurves(t *testing.T) {
+	defer func(p *pbc.Pairing, s abstract.Suite, pubs []abstract.Point, secs []abstract.Scalar, d []*dkg.DistKeyGenerator) {
+		pairing, suite, partPubs, partSec, dkgs = p, s, pubs, secs, d
+	}(pairing, suite, partPubs, partSec, dkgs)
+	for _, curve := range []int{pbc.CurveFp382_2, pbc.CurveBLS12_381} {
+		if !pbc.Supported(curve) {
+			continue
+		}
+		pairing = pbc.NewPairing(curve)
+		suite = pairing.G2()
+		partPubs = make([]abstract.Point, nbParticipants)
+		partSec = make([]abstract.Scalar, nbParticipants)
+		for i := range partSec {
+			partSec[i], partPubs[i] = genPair()
+		}
+		TestThresholdBLS(t)
+	}
+}
+
+func BenchmarkThresholdVerify(b *testing.B) {
+	fullExchange(b)
+	dks, err := dkgs[0].DistKeyShare()
+	require.Nil(b, err)
+	msg := []byte("Hello World")
+	tsig := ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !ThresholdVerify(pairing, DefaultCiphersuite(pairing), dks.Polynomial(), msg, tsig) {
+			b.Fatal("invalid threshold signature")
+		}
+	}
+}
+
+func BenchmarkAggregateSignatures(b *testing.B) {
+	fullExchange(b)
+	msg := []byte("Hello World")
+	sigs := make([]*ThresholdSig, nbParticipants)
+	var poly *share.PubPoly
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.Nil(b, err)
+		sigs[i] = ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
+		poly = dks.Polynomial()
+	}
+	tt := nbParticipants/2 + 1
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AggregateSignatures(pairing, DefaultCiphersuite(pairing), poly, msg, sigs, nbParticipants, tt); err != nil {
+			b.Fatal(err)
+		}
+	}
 }
 
 func dkgGen() []*dkg.DistKeyGenerator {
@@ -74,7 +131,7 @@ func dkgGen() []*dkg.DistKeyGenerator {
 	return dkgs
 }
 
-func fullExchange(t *testing.T) {
+func fullExchange(t testing.TB) {
 	dkgs = dkgGen()
 	// full secret sharing exchange
 	// 1. broadcast deals
@@ -107,3 +164,69 @@ func genPair() (abstract.Scalar, abstract.Point) {
 	sc := suite.Scalar().Pick(random.Stream)
 	return sc, suite.Point().Mul(nil, sc)
 }
+
+func TestThresholdBatchVerify(t *testing.T) {
+	fullExchange(t)
+	msg := []byte("Hello World")
+	sigs := make([]*ThresholdSig, nbParticipants)
+	var public *share.PubPoly
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.Nil(t, err)
+		public = dks.Polynomial()
+		sigs[i] = ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
+	}
+	require.Nil(t, ThresholdBatchVerify(pairing, DefaultCiphersuite(pairing), public, msg, sigs))
+	require.Error(t, ThresholdBatchVerify(pairing, "APP_A_", public, msg, sigs))
+
+	// a share signed by another index and a share of another message
+	bad := append([]*ThresholdSig(nil), sigs...)
+	bad[1] = &ThresholdSig{Index: sigs[2].Index, Sig: sigs[1].Sig}
+	dks, err := dkgs[4].DistKeyShare()
+	require.Nil(t, err)
+	bad[4] = ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, []byte("evil message"))
+	bad[5] = nil
+	err = ThresholdBatchVerify(pairing, DefaultCiphersuite(pairing), public, msg, bad)
+	require.Equal(t, &BatchError{Invalid: []int{1, 4, 5}}, err)
+
+	// the valid shares are enough to recover the signature
+	tt := nbParticipants/2 + 1
+	sig, err := AggregateSignatures(pairing, DefaultCiphersuite(pairing), public, msg, bad, nbParticipants, tt)
+	require.Nil(t, err)
+	require.Nil(t, Verify(pairing, DefaultCiphersuite(pairing), public.Commit(), msg, sig))
+}
+
+func TestRobustAggregateSignatures(t *testing.T) {
+	fullExchange(t)
+	msg := []byte("Hello World")
+	sigs := make([]*ThresholdSig, nbParticipants)
+	var public *share.PubPoly
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.Nil(t, err)
+		public = dks.Polynomial()
+		sigs[i] = ThresholdSign(pairing, DefaultCiphersuite(pairing), dks, msg)
+	}
+	tt := nbParticipants/2 + 1
+
+	// the optimistic recombination does not look further than t shares
+	sig, blames, err := RobustAggregateSignatures(pairing, DefaultCiphersuite(pairing), public, msg, sigs, nbParticipants, tt)
+	require.Nil(t, err)
+	require.Nil(t, blames)
+	require.Nil(t, Verify(pairing, DefaultCiphersuite(pairing), public.Commit(), msg, sig))
+
+	bad := append([]*ThresholdSig{nil}, sigs...)
+	bad[1] = &ThresholdSig{Index: sigs[0].Index, Sig: sigs[1].Sig}
+	bad[3] = &ThresholdSig{Index: nbParticipants, Sig: sigs[2].Sig}
+	sig, blames, err = RobustAggregateSignatures(pairing, DefaultCiphersuite(pairing), public, msg, bad, nbParticipants, tt)
+	require.Nil(t, err)
+	require.Nil(t, Verify(pairing, DefaultCiphersuite(pairing), public.Commit(), msg, sig))
+	require.Len(t, blames, 3)
+	require.Equal(t, &Blame{Index: -1, Reason: ErrMalformedShare}, blames[0])
+	require.Equal(t, &Blame{Index: nbParticipants, Sig: bad[3], Reason: ErrMalformedShare}, blames[1])
+	require.Equal(t, &Blame{Index: sigs[0].Index, Sig: bad[1], Reason: ErrInvalidShare}, blames[2])
+
+	_, blames, err = RobustAggregateSignatures(pairing, DefaultCiphersuite(pairing), public, msg, bad[:tt+1], nbParticipants, tt)
+	require.Equal(t, ErrNotEnoughShares, err)
+	require.Len(t, blames, 3)
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dedis/paper_17_dfinity/poly"
 	"gopkg.in/dedis/crypto.v0/abstract"
 
 	"gopkg.in/dedis/crypto.v0/eddsa"
@@ -184,7 +185,7 @@ func (d *DSS) Signature() ([]byte, error) {
 	if !d.EnoughPartialSig() {
 		return nil, errors.New("dkg: not enough partial signatures to sign")
 	}
-	gamma, err := share.RecoverSecret(d.suite, d.partials, d.T, len(d.participants))
+	gamma, err := poly.RecoverSecret(d.suite, d.partials, d.T, len(d.participants))
 	if err != nil {
 		fmt.Println("or here")
 		return nil, err
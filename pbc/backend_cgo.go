@@ -0,0 +1,119 @@
+//go:build !purego
+// +build !purego
+
+package pbc
+
+import (
+	"sync"
+
+	"github.com/dfinity/go-dfinity-crypto/bls"
+)
+
+// Backend is the name of the library doing the curve arithmetic. The default
+// backend is mcl through its cgo bindings; building with the "purego" tag
+// selects the pure Go implementation instead.
+const Backend = "mcl"
+
+// The types and functions below are the only entry points into the backend
+// used by the rest of the package.
+type (
+	g1 = bls.G1
+	g2 = bls.G2
+	gt = bls.GT
+	fr = bls.Fr
+)
+
+// The functions below return an element of the given curve, to be set by the
+// operations. The elements of mcl do not depend on the curve, which is held
+// by withCurve instead.
+
+func newG1(curve int) g1 {
+	return g1{}
+}
+
+func newG2(curve int) g2 {
+	return g2{}
+}
+
+func newGT(curve int) gt {
+	return gt{}
+}
+
+func newFr(curve int) fr {
+	return fr{}
+}
+
+var (
+	g1Add = bls.G1Add
+	g1Sub = bls.G1Sub
+	g1Neg = bls.G1Neg
+	g1Mul = bls.G1Mul
+
+	g2Add = bls.G2Add
+	g2Sub = bls.G2Sub
+	g2Neg = bls.G2Neg
+	g2Mul = bls.G2Mul
+
+	gtMul = bls.GTMul
+	gtDiv = bls.GTDiv
+	gtInv = bls.GTInv
+	gtPow = bls.GTPow
+
+	pairing = bls.Pairing
+
+	frAdd = bls.FrAdd
+	frSub = bls.FrSub
+	frMul = bls.FrMul
+	frDiv = bls.FrDiv
+	frNeg = bls.FrNeg
+	frInv = bls.FrInv
+)
+
+// pairingProduct sets z to the product of the pairings e(ps[i], qs[i]). The
+// bindings of mcl do not expose the Miller loop on its own, so every pairing
+// goes through its own final exponentiation.
+func pairingProduct(z *gt, ps []*g1, qs []*g2) {
+	var t gt
+	z.SetInt64(1)
+	for i := range ps {
+		bls.Pairing(&t, ps[i], qs[i])
+		bls.GTMul(z, z, &t)
+	}
+}
+
+// supported returns true if the backend implements the given curve.
+func supported(curve int) bool {
+	return curve == CurveFp254BNb || curve == CurveFp382_1 || curve == CurveFp382_2
+}
+
+// The C library keeps the parameters of the curve in a global context that
+// bls.Init() overwrites. Every call into the library goes through withCurve so
+// the context matches the curve of the objects being used: operations on the
+// same curve run concurrently, switching to another curve waits for all
+// pending operations and re-initializes the library.
+var curveCtx struct {
+	sync.RWMutex
+	current int
+	init    bool
+}
+
+// withCurve sets up the C library for the given curve and returns the
+// function to call once the operation is done. Calls MUST NOT be nested.
+func withCurve(curve int) func() {
+	for {
+		curveCtx.RLock()
+		if curveCtx.init && curveCtx.current == curve {
+			return curveCtx.RUnlock
+		}
+		curveCtx.RUnlock()
+
+		curveCtx.Lock()
+		if !curveCtx.init || curveCtx.current != curve {
+			bls.Init(curve)
+			curveCtx.current = curve
+			curveCtx.init = true
+		}
+		curveCtx.Unlock()
+		// someone else might have switched curve in between, check again
+	}
+}
@@ -0,0 +1,156 @@
+//go:build !purego
+// +build !purego
+
+package pbc
+
+import (
+	"testing"
+
+	"github.com/dedis/paper_17_dfinity/pbc/bn254"
+	"github.com/dfinity/go-dfinity-crypto/bls"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/random"
+)
+
+// The tests below check the pure Go backend gives exactly the same results as
+// mcl on CurveFp254BNb, by running the same operations on both and comparing
+// the serializations.
+
+func diffScalar(t *testing.T) (*bls.Fr, *bn254.Fr) {
+	buff := random.Bytes(32, random.Stream)
+	var a bls.Fr
+	var b bn254.Fr
+	require.Nil(t, a.SetLittleEndian(buff))
+	require.Nil(t, b.SetLittleEndian(buff))
+	require.Equal(t, a.Serialize(), b.Serialize())
+	return &a, &b
+}
+
+func TestBackendDiffScalar(t *testing.T) {
+	defer withCurve(CurveFp254BNb)()
+	for i := 0; i < 20; i++ {
+		a1, b1 := diffScalar(t)
+		a2, b2 := diffScalar(t)
+		var a bls.Fr
+		var b bn254.Fr
+		bls.FrAdd(&a, a1, a2)
+		bn254.FrAdd(&b, b1, b2)
+		require.Equal(t, a.Serialize(), b.Serialize())
+		bls.FrSub(&a, a1, a2)
+		bn254.FrSub(&b, b1, b2)
+		require.Equal(t, a.Serialize(), b.Serialize())
+		bls.FrMul(&a, a1, a2)
+		bn254.FrMul(&b, b1, b2)
+		require.Equal(t, a.Serialize(), b.Serialize())
+		bls.FrDiv(&a, a1, a2)
+		bn254.FrDiv(&b, b1, b2)
+		require.Equal(t, a.Serialize(), b.Serialize())
+		bls.FrNeg(&a, a1)
+		bn254.FrNeg(&b, b1)
+		require.Equal(t, a.Serialize(), b.Serialize())
+		require.Equal(t, a.GetString(16), b.GetString(16))
+
+		var b3 bn254.Fr
+		require.Nil(t, b3.Deserialize(a.Serialize()))
+		require.True(t, b3.IsEqual(&b))
+	}
+}
+
+func TestBackendDiffG1(t *testing.T) {
+	defer withCurve(CurveFp254BNb)()
+	var a bls.G1
+	var b bn254.G1
+	require.Nil(t, a.HashAndMapTo([]byte(Fp254_G1_Base_Str)))
+	require.Nil(t, b.HashAndMapTo([]byte(Fp254_G1_Base_Str)))
+	require.Equal(t, a.Serialize(), b.Serialize())
+	for i := 0; i < 10; i++ {
+		msg := random.Bytes(32, random.Stream)
+		var a2 bls.G1
+		var b2 bn254.G1
+		require.Nil(t, a2.HashAndMapTo(msg))
+		require.Nil(t, b2.HashAndMapTo(msg))
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+		require.Equal(t, a2.GetString(16), b2.GetString(16))
+
+		sa, sb := diffScalar(t)
+		bls.G1Mul(&a2, &a2, sa)
+		bn254.G1Mul(&b2, &b2, sb)
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+		bls.G1Add(&a2, &a2, &a)
+		bn254.G1Add(&b2, &b2, &b)
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+		bls.G1Sub(&a2, &a2, &a)
+		bn254.G1Sub(&b2, &b2, &b)
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+
+		var b3 bn254.G1
+		require.Nil(t, b3.Deserialize(a2.Serialize()))
+		require.True(t, b3.IsEqual(&b2))
+		var a3 bls.G1
+		require.Nil(t, a3.Deserialize(b2.Serialize()))
+		require.True(t, a3.IsEqual(&a2))
+	}
+}
+
+func TestBackendDiffG2(t *testing.T) {
+	defer withCurve(CurveFp254BNb)()
+	var a bls.G2
+	var b bn254.G2
+	require.Nil(t, a.HashAndMapTo([]byte(Fp254_G2_Base_Str)))
+	require.Nil(t, b.HashAndMapTo([]byte(Fp254_G2_Base_Str)))
+	require.Equal(t, a.Serialize(), b.Serialize())
+	for i := 0; i < 10; i++ {
+		msg := random.Bytes(32, random.Stream)
+		var a2 bls.G2
+		var b2 bn254.G2
+		require.Nil(t, a2.HashAndMapTo(msg))
+		require.Nil(t, b2.HashAndMapTo(msg))
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+		require.Equal(t, a2.GetString(16), b2.GetString(16))
+
+		sa, sb := diffScalar(t)
+		bls.G2Mul(&a2, &a2, sa)
+		bn254.G2Mul(&b2, &b2, sb)
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+		bls.G2Add(&a2, &a2, &a)
+		bn254.G2Add(&b2, &b2, &b)
+		require.Equal(t, a2.Serialize(), b2.Serialize())
+
+		var b3 bn254.G2
+		require.Nil(t, b3.Deserialize(a2.Serialize()))
+		require.True(t, b3.IsEqual(&b2))
+		var a3 bls.G2
+		require.Nil(t, a3.Deserialize(b2.Serialize()))
+		require.True(t, a3.IsEqual(&a2))
+	}
+}
+
+func TestBackendDiffPairing(t *testing.T) {
+	defer withCurve(CurveFp254BNb)()
+	for i := 0; i < 5; i++ {
+		msg := random.Bytes(32, random.Stream)
+		var a1 bls.G1
+		var b1 bn254.G1
+		require.Nil(t, a1.HashAndMapTo(msg))
+		require.Nil(t, b1.HashAndMapTo(msg))
+		var a2 bls.G2
+		var b2 bn254.G2
+		require.Nil(t, a2.HashAndMapTo(msg))
+		require.Nil(t, b2.HashAndMapTo(msg))
+
+		var a bls.GT
+		var b bn254.GT
+		bls.Pairing(&a, &a1, &a2)
+		bn254.Pairing(&b, &b1, &b2)
+		require.Equal(t, a.Serialize(), b.Serialize())
+
+		sa, sb := diffScalar(t)
+		bls.GTPow(&a, &a, sa)
+		bn254.GTPow(&b, &b, sb)
+		require.Equal(t, a.Serialize(), b.Serialize())
+
+		var b3 bn254.GT
+		require.Nil(t, b3.Deserialize(a.Serialize()))
+		require.True(t, b3.IsEqual(&b))
+	}
+}
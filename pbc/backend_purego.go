@@ -0,0 +1,487 @@
+//go:build purego
+// +build purego
+
+package pbc
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/dedis/paper_17_dfinity/pbc/bls12381"
+	"github.com/dedis/paper_17_dfinity/pbc/bn254"
+)
+
+// Backend is the name of the library doing the curve arithmetic. Building
+// with the "purego" tag selects the pure Go implementations, which support
+// CurveFp254BNb and CurveBLS12_381 but do not need cgo.
+const Backend = "purego"
+
+// The types and functions below are the only entry points into the backend
+// used by the rest of the package. Every element holds a single element of
+// the curve it was created for by newG1, newG2, newGT or newFr, in a storage
+// sized for the largest of the two curves, and the operations run the
+// implementation of the curve of their operands. Unlike mcl, the backend has
+// no global state.
+type (
+	g1 struct {
+		curve int
+		v     [g1Words]uint64
+	}
+	g2 struct {
+		curve int
+		v     [g2Words]uint64
+	}
+	gt struct {
+		curve int
+		v     [gtWords]uint64
+	}
+	fr struct {
+		curve int
+		v     [frWords]uint64
+	}
+)
+
+const (
+	g1Words = unsafe.Sizeof(bls12381.G1{}) / 8
+	g2Words = unsafe.Sizeof(bls12381.G2{}) / 8
+	gtWords = unsafe.Sizeof(bls12381.GT{}) / 8
+	frWords = unsafe.Sizeof(bls12381.Fr{}) / 8
+)
+
+// The elements of BN254 are smaller and do not compile otherwise.
+const (
+	_ = g1Words*8 - unsafe.Sizeof(bn254.G1{})
+	_ = g2Words*8 - unsafe.Sizeof(bn254.G2{})
+	_ = gtWords*8 - unsafe.Sizeof(bn254.GT{})
+	_ = frWords*8 - unsafe.Sizeof(bn254.Fr{})
+)
+
+// The functions below return an element of the given curve, to be set by the
+// operations.
+
+func newG1(curve int) g1 {
+	return g1{curve: curve}
+}
+
+func newG2(curve int) g2 {
+	return g2{curve: curve}
+}
+
+func newGT(curve int) gt {
+	return gt{curve: curve}
+}
+
+func newFr(curve int) fr {
+	return fr{curve: curve}
+}
+
+// onBLS returns true if curve is BLS12-381 rather than BN254.
+func onBLS(curve int) bool {
+	return curve == CurveBLS12_381
+}
+
+// The methods below view the storage of the elements as the ones of either
+// implementation. Both are arrays of words without Go pointers.
+
+func (p *g1) bn() *bn254.G1     { return (*bn254.G1)(unsafe.Pointer(&p.v)) }
+func (p *g1) bls() *bls12381.G1 { return (*bls12381.G1)(unsafe.Pointer(&p.v)) }
+func (p *g2) bn() *bn254.G2     { return (*bn254.G2)(unsafe.Pointer(&p.v)) }
+func (p *g2) bls() *bls12381.G2 { return (*bls12381.G2)(unsafe.Pointer(&p.v)) }
+func (x *gt) bn() *bn254.GT     { return (*bn254.GT)(unsafe.Pointer(&x.v)) }
+func (x *gt) bls() *bls12381.GT { return (*bls12381.GT)(unsafe.Pointer(&x.v)) }
+func (x *fr) bn() *bn254.Fr     { return (*bn254.Fr)(unsafe.Pointer(&x.v)) }
+func (x *fr) bls() *bls12381.Fr { return (*bls12381.Fr)(unsafe.Pointer(&x.v)) }
+
+var errNoMapTo = errors.New("pbc: HashAndMapTo is not implemented on BLS12_381")
+
+// The operations are variables, as in the cgo backend, so that tests can
+// wrap them.
+var g1Add = func(z, x, y *g1) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G1Add(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.G1Add(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var g1Sub = func(z, x, y *g1) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G1Sub(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.G1Sub(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var g1Neg = func(z, x *g1) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G1Neg(z.bls(), x.bls())
+	} else {
+		bn254.G1Neg(z.bn(), x.bn())
+	}
+}
+
+var g1Mul = func(z, x *g1, s *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G1Mul(z.bls(), x.bls(), s.bls())
+	} else {
+		bn254.G1Mul(z.bn(), x.bn(), s.bn())
+	}
+}
+
+var g2Add = func(z, x, y *g2) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G2Add(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.G2Add(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var g2Sub = func(z, x, y *g2) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G2Sub(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.G2Sub(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var g2Neg = func(z, x *g2) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G2Neg(z.bls(), x.bls())
+	} else {
+		bn254.G2Neg(z.bn(), x.bn())
+	}
+}
+
+var g2Mul = func(z, x *g2, s *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.G2Mul(z.bls(), x.bls(), s.bls())
+	} else {
+		bn254.G2Mul(z.bn(), x.bn(), s.bn())
+	}
+}
+
+var gtMul = func(z, x, y *gt) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.GTMul(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.GTMul(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var gtDiv = func(z, x, y *gt) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.GTDiv(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.GTDiv(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var gtInv = func(z, x *gt) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.GTInv(z.bls(), x.bls())
+	} else {
+		bn254.GTInv(z.bn(), x.bn())
+	}
+}
+
+var gtPow = func(z, x *gt, s *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.GTPow(z.bls(), x.bls(), s.bls())
+	} else {
+		bn254.GTPow(z.bn(), x.bn(), s.bn())
+	}
+}
+
+var pairing = func(z *gt, x *g1, y *g2) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.Pairing(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.Pairing(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var frAdd = func(z, x, y *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrAdd(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.FrAdd(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var frSub = func(z, x, y *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrSub(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.FrSub(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var frMul = func(z, x, y *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrMul(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.FrMul(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var frDiv = func(z, x, y *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrDiv(z.bls(), x.bls(), y.bls())
+	} else {
+		bn254.FrDiv(z.bn(), x.bn(), y.bn())
+	}
+}
+
+var frNeg = func(z, x *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrNeg(z.bls(), x.bls())
+	} else {
+		bn254.FrNeg(z.bn(), x.bn())
+	}
+}
+
+var frInv = func(z, x *fr) {
+	z.curve = x.curve
+	if onBLS(x.curve) {
+		bls12381.FrInv(z.bls(), x.bls())
+	} else {
+		bn254.FrInv(z.bn(), x.bn())
+	}
+}
+
+// pairingProduct sets z to the product of the pairings e(ps[i], qs[i]),
+// sharing a single final exponentiation between all the Miller loops.
+func pairingProduct(z *gt, ps []*g1, qs []*g2) {
+	if onBLS(z.curve) {
+		bps := make([]*bls12381.G1, len(ps))
+		bqs := make([]*bls12381.G2, len(qs))
+		for i := range ps {
+			bps[i], bqs[i] = ps[i].bls(), qs[i].bls()
+		}
+		bls12381.PairingProduct(z.bls(), bps, bqs)
+		return
+	}
+	var f, t bn254.GT
+	f.SetInt64(1)
+	for i := range ps {
+		bn254.MillerLoop(&t, ps[i].bn(), qs[i].bn())
+		bn254.GTMul(&f, &f, &t)
+	}
+	bn254.FinalExp(z.bn(), &f)
+}
+
+func (p *g1) Clear() {
+	if onBLS(p.curve) {
+		p.bls().Clear()
+	} else {
+		p.bn().Clear()
+	}
+}
+
+func (p *g1) IsEqual(q *g1) bool {
+	if onBLS(p.curve) {
+		return p.bls().IsEqual(q.bls())
+	}
+	return p.bn().IsEqual(q.bn())
+}
+
+func (p *g1) Serialize() []byte {
+	if onBLS(p.curve) {
+		return p.bls().Serialize()
+	}
+	return p.bn().Serialize()
+}
+
+func (p *g1) Deserialize(buff []byte) error {
+	if onBLS(p.curve) {
+		return p.bls().Deserialize(buff)
+	}
+	return p.bn().Deserialize(buff)
+}
+
+func (p *g1) GetString(base int) string {
+	if onBLS(p.curve) {
+		return p.bls().GetString(base)
+	}
+	return p.bn().GetString(base)
+}
+
+func (p *g1) SetString(s string, base int) error {
+	if onBLS(p.curve) {
+		return p.bls().SetString(s, base)
+	}
+	return p.bn().SetString(s, base)
+}
+
+func (p *g1) HashAndMapTo(msg []byte) error {
+	if onBLS(p.curve) {
+		return errNoMapTo
+	}
+	return p.bn().HashAndMapTo(msg)
+}
+
+func (p *g2) Clear() {
+	if onBLS(p.curve) {
+		p.bls().Clear()
+	} else {
+		p.bn().Clear()
+	}
+}
+
+func (p *g2) IsEqual(q *g2) bool {
+	if onBLS(p.curve) {
+		return p.bls().IsEqual(q.bls())
+	}
+	return p.bn().IsEqual(q.bn())
+}
+
+func (p *g2) Serialize() []byte {
+	if onBLS(p.curve) {
+		return p.bls().Serialize()
+	}
+	return p.bn().Serialize()
+}
+
+func (p *g2) Deserialize(buff []byte) error {
+	if onBLS(p.curve) {
+		return p.bls().Deserialize(buff)
+	}
+	return p.bn().Deserialize(buff)
+}
+
+func (p *g2) GetString(base int) string {
+	if onBLS(p.curve) {
+		return p.bls().GetString(base)
+	}
+	return p.bn().GetString(base)
+}
+
+func (p *g2) SetString(s string, base int) error {
+	if onBLS(p.curve) {
+		return p.bls().SetString(s, base)
+	}
+	return p.bn().SetString(s, base)
+}
+
+func (p *g2) HashAndMapTo(msg []byte) error {
+	if onBLS(p.curve) {
+		return errNoMapTo
+	}
+	return p.bn().HashAndMapTo(msg)
+}
+
+func (x *gt) SetInt64(v int64) {
+	if onBLS(x.curve) {
+		x.bls().SetInt64(v)
+	} else {
+		x.bn().SetInt64(v)
+	}
+}
+
+func (x *gt) IsEqual(y *gt) bool {
+	if onBLS(x.curve) {
+		return x.bls().IsEqual(y.bls())
+	}
+	return x.bn().IsEqual(y.bn())
+}
+
+func (x *gt) Serialize() []byte {
+	if onBLS(x.curve) {
+		return x.bls().Serialize()
+	}
+	return x.bn().Serialize()
+}
+
+func (x *gt) Deserialize(buff []byte) error {
+	if onBLS(x.curve) {
+		return x.bls().Deserialize(buff)
+	}
+	return x.bn().Deserialize(buff)
+}
+
+func (x *gt) GetString(base int) string {
+	if onBLS(x.curve) {
+		return x.bls().GetString(base)
+	}
+	return x.bn().GetString(base)
+}
+
+func (x *gt) SetString(s string, base int) error {
+	if onBLS(x.curve) {
+		return x.bls().SetString(s, base)
+	}
+	return x.bn().SetString(s, base)
+}
+
+func (x *fr) SetInt64(v int64) {
+	if onBLS(x.curve) {
+		x.bls().SetInt64(v)
+	} else {
+		x.bn().SetInt64(v)
+	}
+}
+
+func (x *fr) IsEqual(y *fr) bool {
+	if onBLS(x.curve) {
+		return x.bls().IsEqual(y.bls())
+	}
+	return x.bn().IsEqual(y.bn())
+}
+
+func (x *fr) Serialize() []byte {
+	if onBLS(x.curve) {
+		return x.bls().Serialize()
+	}
+	return x.bn().Serialize()
+}
+
+func (x *fr) Deserialize(buff []byte) error {
+	if onBLS(x.curve) {
+		return x.bls().Deserialize(buff)
+	}
+	return x.bn().Deserialize(buff)
+}
+
+func (x *fr) GetString(base int) string {
+	if onBLS(x.curve) {
+		return x.bls().GetString(base)
+	}
+	return x.bn().GetString(base)
+}
+
+func (x *fr) SetString(s string, base int) error {
+	if onBLS(x.curve) {
+		return x.bls().SetString(s, base)
+	}
+	return x.bn().SetString(s, base)
+}
+
+// supported returns true if the backend implements the given curve.
+func supported(curve int) bool {
+	return curve == CurveFp254BNb || curve == CurveBLS12_381
+}
+
+// withCurve returns the function to call once an operation on the given curve
+// is done, as in the cgo backend. The elements carry their curve, so there is
+// nothing to select.
+func withCurve(curve int) func() {
+	return func() {}
+}
@@ -0,0 +1,57 @@
+//go:build purego
+// +build purego
+
+package pbc
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/dedis/paper_17_dfinity/pbc/bls12381"
+	bls12 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/require"
+)
+
+// TestElementSize checks the elements hold the one of their curve only, in the
+// storage of the largest curve.
+func TestElementSize(t *testing.T) {
+	tag := unsafe.Sizeof(int(0))
+	require.Equal(t, unsafe.Sizeof(bls12381.G1{})+tag, unsafe.Sizeof(g1{}))
+	require.Equal(t, unsafe.Sizeof(bls12381.G2{})+tag, unsafe.Sizeof(g2{}))
+	require.Equal(t, unsafe.Sizeof(bls12381.GT{})+tag, unsafe.Sizeof(gt{}))
+	require.Equal(t, unsafe.Sizeof(bls12381.Fr{})+tag, unsafe.Sizeof(fr{}))
+
+	p, err := NewPairingBLS12_381()
+	require.Nil(t, err)
+	pt := p.G1().Point().Base().(*pointG1)
+	require.Equal(t, CurveBLS12_381, pt.g.curve)
+	p.G1().(Releaser).Release(pt)
+	require.Equal(t, CurveBLS12_381, p.G1().Point().(*pointG1).g.curve)
+}
+
+// TestBLS12_381HashToPoint checks the hash to curve of BLS12-381 gives the
+// same points as the BLS12381G1_XMD:SHA-256_SSWU_RO_ and
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ suites of the library behind the backend.
+func TestBLS12_381HashToPoint(t *testing.T) {
+	p, err := NewPairingBLS12_381()
+	require.Nil(t, err)
+	p.SetPointFormat(Compressed)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	for _, msg := range []string{"", "abc", "abcdef0123456789"} {
+		h1 := p.G1().(PointHasher).HashToPoint([]byte(msg), dst)
+		buff, err := h1.MarshalBinary()
+		require.Nil(t, err)
+		g1 := bls12.NewG1()
+		q1, err := g1.HashToCurve([]byte(msg), dst)
+		require.Nil(t, err)
+		require.Equal(t, g1.ToCompressed(q1), buff, "G1 %q", msg)
+
+		h2 := p.G2().(PointHasher).HashToPoint([]byte(msg), dst)
+		buff, err = h2.MarshalBinary()
+		require.Nil(t, err)
+		g2 := bls12.NewG2()
+		q2, err := g2.HashToCurve([]byte(msg), dst)
+		require.Nil(t, err)
+		require.Equal(t, g2.ToCompressed(q2), buff, "G2 %q", msg)
+	}
+}
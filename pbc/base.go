@@ -0,0 +1,166 @@
+package pbc
+
+import "sync"
+
+// baseWindow is the width in bits of the windows of the fixed-base tables.
+// With 4 bits, a multiplication by the generator costs one addition per
+// nibble of the scalar and no doubling.
+const baseWindow = 4
+
+// baseG1 holds the generator of G1 of a Pairing, hashed from its seed the
+// first time it is needed, and the table used by the multiplications by the
+// generator. On BLS12-381, the generator is the standard one given as a
+// string instead.
+type baseG1 struct {
+	curve     int
+	generator string
+
+	genOnce   sync.Once
+	gen       g1
+	tableOnce sync.Once
+	// table[i][j-1] = j * 2^(baseWindow*i) * gen for 0 < j < 2^baseWindow
+	table [][]g1
+}
+
+func newBaseG1(curve int) *baseG1 {
+	return &baseG1{curve: curve, generator: generator(curve, 0), gen: newG1(curve)}
+}
+
+// get returns the generator. It must not be called while holding the curve.
+func (b *baseG1) get() *g1 {
+	b.genOnce.Do(func() {
+		defer withCurve(b.curve)()
+		if err := setGenerator(&b.gen, b.curve, b.generator); err != nil {
+			panic(err)
+		}
+	})
+	return &b.gen
+}
+
+type generatorSetter interface {
+	stringer
+	HashAndMapTo(msg []byte) error
+}
+
+// setGenerator sets p to the generator given by s. It must be called while
+// holding the curve.
+func setGenerator(p generatorSetter, curve int, s string) error {
+	if curve == CurveBLS12_381 {
+		return p.SetString(s, 16)
+	}
+	return p.HashAndMapTo([]byte(s))
+}
+
+// mul sets z = s * gen using the table. It must not be called while holding
+// the curve.
+func (b *baseG1) mul(z *g1, s *fr) {
+	gen := b.get()
+	b.tableOnce.Do(func() {
+		defer withCurve(b.curve)()
+		b.table = make([][]g1, scalarWindows(b.curve))
+		acc := *gen
+		for i := range b.table {
+			row := make([]g1, 1<<baseWindow-1)
+			row[0] = acc
+			for j := 1; j < len(row); j++ {
+				g1Add(&row[j], &row[j-1], &acc)
+			}
+			g1Add(&acc, &row[len(row)-1], &acc)
+			b.table[i] = row
+		}
+	})
+	defer withCurve(b.curve)()
+	acc := newG1(b.curve)
+	acc.Clear()
+	for i, d := range scalarDigits(s) {
+		if d != 0 {
+			g1Add(&acc, &acc, &b.table[i][d-1])
+		}
+	}
+	*z = acc
+}
+
+// baseG2 is the G2 version of baseG1.
+type baseG2 struct {
+	curve     int
+	generator string
+
+	genOnce   sync.Once
+	gen       g2
+	tableOnce sync.Once
+	table     [][]g2
+}
+
+func newBaseG2(curve int) *baseG2 {
+	return &baseG2{curve: curve, generator: generator(curve, 1), gen: newG2(curve)}
+}
+
+func (b *baseG2) get() *g2 {
+	b.genOnce.Do(func() {
+		defer withCurve(b.curve)()
+		if err := setGenerator(&b.gen, b.curve, b.generator); err != nil {
+			panic(err)
+		}
+	})
+	return &b.gen
+}
+
+func (b *baseG2) mul(z *g2, s *fr) {
+	gen := b.get()
+	b.tableOnce.Do(func() {
+		defer withCurve(b.curve)()
+		b.table = make([][]g2, scalarWindows(b.curve))
+		acc := *gen
+		for i := range b.table {
+			row := make([]g2, 1<<baseWindow-1)
+			row[0] = acc
+			for j := 1; j < len(row); j++ {
+				g2Add(&row[j], &row[j-1], &acc)
+			}
+			g2Add(&acc, &row[len(row)-1], &acc)
+			b.table[i] = row
+		}
+	})
+	defer withCurve(b.curve)()
+	acc := newG2(b.curve)
+	acc.Clear()
+	for i, d := range scalarDigits(s) {
+		if d != 0 {
+			g2Add(&acc, &acc, &b.table[i][d-1])
+		}
+	}
+	*z = acc
+}
+
+// baseGT holds the generator of GT, the pairing of the generators of G1 and
+// G2.
+type baseGT struct {
+	curve int
+	once  sync.Once
+	gen   gt
+}
+
+func (b *baseGT) get(g1 *baseG1, g2 *baseG2) *gt {
+	b.once.Do(func() {
+		p, q := g1.get(), g2.get()
+		defer withCurve(b.curve)()
+		pairing(&b.gen, p, q)
+	})
+	return &b.gen
+}
+
+// scalarWindows returns the number of windows of a scalar of the curve.
+func scalarWindows(curve int) int {
+	return scalarSize(curve) * 8 / baseWindow
+}
+
+// scalarDigits returns the windows of s from the least significant one. It
+// must be called while holding the curve.
+func scalarDigits(s *fr) []byte {
+	buff := s.Serialize()
+	digits := make([]byte, 0, 2*len(buff))
+	for _, c := range buff {
+		digits = append(digits, c&0xf, c>>4)
+	}
+	return digits
+}
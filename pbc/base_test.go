@@ -0,0 +1,82 @@
+package pbc
+
+import (
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseMul(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Group{p.G1(), p.G2(), p.GT()} {
+		base := g.Point().Base()
+		scalars := []abstract.Scalar{
+			g.Scalar().Zero(),
+			g.Scalar().One(),
+			g.Scalar().SetInt64(-1),
+			g.Scalar().SetInt64(16),
+			g.Scalar().Pick(random.Stream),
+		}
+		for _, s := range scalars {
+			exp := g.Point().Mul(base, s)
+			require.True(t, g.Point().Mul(nil, s).Equal(exp), "%s: %s", g, s)
+		}
+		// the cached generator is not modified through the points
+		base.Add(base, base)
+		require.False(t, g.Point().Base().Equal(base))
+	}
+
+	gt := p.GT().PointGT().Pairing(p.G1().Point().Base(), p.G2().Point().Base())
+	require.True(t, p.GT().Point().Base().Equal(gt))
+}
+
+func BenchmarkG1Base(b *testing.B) {
+	benchmarkBase(b, NewPairingFp254BNb().G1())
+}
+
+func BenchmarkG2Base(b *testing.B) {
+	benchmarkBase(b, NewPairingFp254BNb().G2())
+}
+
+func BenchmarkGTBase(b *testing.B) {
+	benchmarkBase(b, NewPairingFp254BNb().GT())
+}
+
+func BenchmarkG1Mul(b *testing.B) {
+	benchmarkMul(b, NewPairingFp254BNb().G1(), false)
+}
+
+func BenchmarkG1MulBase(b *testing.B) {
+	benchmarkMul(b, NewPairingFp254BNb().G1(), true)
+}
+
+func BenchmarkG2Mul(b *testing.B) {
+	benchmarkMul(b, NewPairingFp254BNb().G2(), false)
+}
+
+func BenchmarkG2MulBase(b *testing.B) {
+	benchmarkMul(b, NewPairingFp254BNb().G2(), true)
+}
+
+func benchmarkBase(b *testing.B, g abstract.Group) {
+	p := g.Point()
+	for i := 0; i < b.N; i++ {
+		p.Base()
+	}
+}
+
+func benchmarkMul(b *testing.B, g abstract.Group, base bool) {
+	s := g.Scalar().Pick(random.Stream)
+	var p1 abstract.Point
+	if !base {
+		p1 = g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+	}
+	p := g.Point().Mul(nil, s) // build the tables outside of the timer
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Mul(p1, s)
+	}
+}
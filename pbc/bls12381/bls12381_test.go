@@ -0,0 +1,174 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The generators of G1 and G2 given by the specification of the curve, in
+// the format of GetString.
+const (
+	g1Gen = "1 17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb 8b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1"
+	g2Gen = "1 24aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8 13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801 606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be"
+)
+
+// g1GenCompressed is the compressed encoding of the generator of G1 from the
+// ZCash test vectors.
+const g1GenCompressed = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+
+func randFr(t *testing.T) (*Fr, *big.Int) {
+	b, err := rand.Int(rand.Reader, order)
+	require.Nil(t, err)
+	var x Fr
+	x.SetBig(b)
+	return &x, b
+}
+
+func generators(t *testing.T) (*G1, *G2) {
+	var p G1
+	var q G2
+	require.Nil(t, p.SetString(g1Gen, 16))
+	require.Nil(t, q.SetString(g2Gen, 16))
+	return &p, &q
+}
+
+func TestFrArithmetic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		x, xb := randFr(t)
+		y, yb := randFr(t)
+		var z Fr
+		exp := new(big.Int)
+
+		FrAdd(&z, x, y)
+		require.Equal(t, exp.Add(xb, yb).Mod(exp, order), z.Big())
+		FrSub(&z, x, y)
+		require.Equal(t, exp.Sub(xb, yb).Mod(exp, order), z.Big())
+		FrMul(&z, x, y)
+		require.Equal(t, exp.Mul(xb, yb).Mod(exp, order), z.Big())
+		FrNeg(&z, x)
+		require.Equal(t, exp.Neg(xb).Mod(exp, order), z.Big())
+		FrInv(&z, x)
+		require.Equal(t, exp.ModInverse(xb, order), z.Big())
+		FrDiv(&z, x, y)
+		FrMul(&z, &z, y)
+		require.True(t, z.IsEqual(x))
+
+		var d Fr
+		require.Nil(t, d.Deserialize(x.Serialize()))
+		require.True(t, d.IsEqual(x))
+	}
+
+	var x Fr
+	x.SetInt64(-1)
+	require.Equal(t, new(big.Int).Sub(order, big.NewInt(1)), x.Big())
+	FrNeg(&x, &x)
+	require.True(t, x.IsOne())
+	buff := make([]byte, FrSize)
+	for i, c := range order.Bytes() {
+		buff[FrSize-1-i] = c
+	}
+	require.NotNil(t, x.Deserialize(buff))
+}
+
+func TestG1(t *testing.T) {
+	g, _ := generators(t)
+	require.Equal(t, g1Gen, g.GetString(16))
+	require.Equal(t, g1GenCompressed, hexString(g.Serialize()))
+
+	s, sb := randFr(t)
+	var p, q, r G1
+	G1Mul(&p, g, s)
+	q.Clear()
+	for i := sb.BitLen() - 1; i >= 0; i-- {
+		G1Add(&q, &q, &q)
+		if sb.Bit(i) == 1 {
+			G1Add(&q, &q, g)
+		}
+	}
+	require.True(t, p.IsEqual(&q))
+	G1Sub(&r, &p, &q)
+	require.True(t, r.IsZero())
+	G1Neg(&r, &p)
+	G1Add(&r, &r, &p)
+	require.True(t, r.IsZero())
+
+	require.Nil(t, r.Deserialize(p.Serialize()))
+	require.True(t, r.IsEqual(&p))
+	require.Nil(t, r.SetString(p.GetString(10), 10))
+	require.True(t, r.IsEqual(&p))
+	r.Clear()
+	require.Equal(t, "0", r.GetString(16))
+	require.Nil(t, q.Deserialize(r.Serialize()))
+	require.True(t, q.IsZero())
+
+	require.NotNil(t, r.SetString("1 1 2", 16))
+	require.NotNil(t, r.SetString("1 0 0", 16))
+}
+
+func TestG2(t *testing.T) {
+	_, g := generators(t)
+	require.Equal(t, g2Gen, g.GetString(16))
+
+	s, _ := randFr(t)
+	u, _ := randFr(t)
+	var p, q, r G2
+	G2Mul(&p, g, s)
+	G2Mul(&q, g, u)
+	G2Add(&r, &p, &q)
+	var su Fr
+	FrAdd(&su, s, u)
+	G2Mul(&q, g, &su)
+	require.True(t, r.IsEqual(&q))
+	G2Sub(&r, &r, &p)
+	G2Mul(&q, g, u)
+	require.True(t, r.IsEqual(&q))
+
+	require.Nil(t, r.Deserialize(p.Serialize()))
+	require.True(t, r.IsEqual(&p))
+	require.Nil(t, r.SetString(p.GetString(16), 16))
+	require.True(t, r.IsEqual(&p))
+}
+
+func TestPairing(t *testing.T) {
+	g1, g2 := generators(t)
+	a, _ := randFr(t)
+	b, _ := randFr(t)
+	var p G1
+	var q G2
+	G1Mul(&p, g1, a)
+	G2Mul(&q, g2, b)
+
+	var ab Fr
+	var x, y GT
+	Pairing(&x, &p, &q)
+	Pairing(&y, g1, g2)
+	FrMul(&ab, a, b)
+	GTPow(&y, &y, &ab)
+	require.True(t, x.IsEqual(&y))
+	require.False(t, x.IsOne())
+
+	// e(a g1, b g2) e(-ab g1, g2) = 1
+	var p2 G1
+	G1Mul(&p2, g1, &ab)
+	G1Neg(&p2, &p2)
+	PairingProduct(&x, []*G1{&p, &p2}, []*G2{&q, g2})
+	require.True(t, x.IsOne())
+
+	var z GT
+	require.Nil(t, z.Deserialize(y.Serialize()))
+	require.True(t, z.IsEqual(&y))
+	require.Nil(t, z.SetString(y.GetString(16), 16))
+	require.True(t, z.IsEqual(&y))
+	GTInv(&z, &y)
+	GTMul(&z, &z, &y)
+	require.True(t, z.IsOne())
+	GTDiv(&z, &y, &y)
+	require.True(t, z.IsOne())
+}
+
+func hexString(buff []byte) string {
+	return new(big.Int).SetBytes(buff).Text(16)
+}
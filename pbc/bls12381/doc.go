@@ -0,0 +1,12 @@
+// Package bls12381 exposes the BLS12-381 pairing of github.com/kilic/bls12-381
+// with the API of the pbc backends, i.e. the subset of the
+// go-dfinity-crypto/bls bindings used by the pbc package, as package bn254
+// does for BN254.
+//
+// Points of G1 and G2 serialize to the compressed encoding of ZCash, which
+// every BLS12-381 implementation understands, scalars to 32 little-endian
+// bytes like mcl, and elements of GT to the twelve big-endian coordinates
+// written by kilic/bls12-381.
+//
+// This implementation is not constant time.
+package bls12381
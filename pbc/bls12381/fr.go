@@ -0,0 +1,143 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	bls12 "github.com/kilic/bls12-381"
+)
+
+// order is the order r of G1, G2 and GT and modulus is the characteristic p
+// of the base field.
+var (
+	order, _   = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	modulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+)
+
+// Fr is an element of the scalar field. The zero value is the scalar 0.
+type Fr struct {
+	v bls12.Fr // little-endian words, not in Montgomery form
+}
+
+// FrSize is the size in bytes of a serialized Fr.
+const FrSize = 32
+
+// Order returns the order of the groups G1, G2 and GT.
+func Order() *big.Int {
+	return new(big.Int).Set(order)
+}
+
+// FieldModulus returns the characteristic of the base field.
+func FieldModulus() *big.Int {
+	return new(big.Int).Set(modulus)
+}
+
+// Clear sets x to zero.
+func (x *Fr) Clear() {
+	x.v.Zero()
+}
+
+// SetInt64 sets x to v modulo the order.
+func (x *Fr) SetInt64(v int64) {
+	x.SetBig(big.NewInt(v))
+}
+
+// SetBig sets x to b modulo the order.
+func (x *Fr) SetBig(b *big.Int) {
+	v := new(big.Int).Mod(b, order)
+	x.v.FromBytes(v.Bytes())
+}
+
+// Big returns the canonical value of x.
+func (x *Fr) Big() *big.Int {
+	return x.v.ToBig()
+}
+
+// IsEqual returns true if both scalars are equal.
+func (x *Fr) IsEqual(y *Fr) bool {
+	return x.v.Equal(&y.v)
+}
+
+// IsZero returns true if x is zero.
+func (x *Fr) IsZero() bool {
+	return x.v.IsZero()
+}
+
+// IsOne returns true if x is one.
+func (x *Fr) IsOne() bool {
+	return x.v.IsOne()
+}
+
+// Serialize returns the little-endian canonical encoding of x on FrSize bytes.
+func (x *Fr) Serialize() []byte {
+	buff := make([]byte, FrSize)
+	for i := range buff {
+		buff[i] = byte(x.v[i/8] >> (8 * uint(i%8)))
+	}
+	return buff
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the size is wrong or if the value is not reduced modulo the order.
+func (x *Fr) Deserialize(buff []byte) error {
+	if len(buff) != FrSize {
+		return errors.New("bls12381: wrong size for Fr")
+	}
+	var v bls12.Fr
+	for i, c := range buff {
+		v[i/8] |= uint64(c) << (8 * uint(i%8))
+	}
+	if v.ToBig().Cmp(order) >= 0 {
+		return errors.New("bls12381: value is not reduced")
+	}
+	x.v = v
+	return nil
+}
+
+// GetString returns the value of x in the given base (10 or 16).
+func (x *Fr) GetString(base int) string {
+	return x.Big().Text(base)
+}
+
+// SetString reads a value in the given base. It returns an error if the value
+// is not reduced.
+func (x *Fr) SetString(s string, base int) error {
+	b, ok := new(big.Int).SetString(s, base)
+	if !ok || b.Sign() < 0 || b.Cmp(order) >= 0 {
+		return errors.New("bls12381: invalid Fr string")
+	}
+	x.SetBig(b)
+	return nil
+}
+
+// FrAdd sets z = x + y.
+func FrAdd(z, x, y *Fr) {
+	z.v.Add(&x.v, &y.v)
+}
+
+// FrSub sets z = x - y.
+func FrSub(z, x, y *Fr) {
+	z.v.Sub(&x.v, &y.v)
+}
+
+// FrMul sets z = x * y.
+func FrMul(z, x, y *Fr) {
+	z.v.Mul(&x.v, &y.v)
+}
+
+// FrNeg sets z = -x.
+func FrNeg(z, x *Fr) {
+	z.v.Neg(&x.v)
+}
+
+// FrInv sets z = 1 / x. The inverse of zero is zero.
+func FrInv(z, x *Fr) {
+	z.v.Inverse(&x.v)
+}
+
+// FrDiv sets z = x / y.
+func FrDiv(z, x, y *Fr) {
+	var t Fr
+	FrInv(&t, y)
+	FrMul(z, x, &t)
+}
@@ -0,0 +1,168 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	bls12 "github.com/kilic/bls12-381"
+)
+
+// G1Size is the size in bytes of a serialized G1 point.
+const G1Size = 48
+
+// fpSize is the size in bytes of an element of the base field.
+const fpSize = 48
+
+// G1 is a point on the curve y^2 = x^3 + 4 over Fp. The zero value is the
+// point at infinity.
+type G1 struct {
+	p bls12.PointG1
+}
+
+// The groups of kilic/bls12-381 hold scratch space, so they are not safe for
+// concurrent use. Every operation takes one from a pool.
+var g1Groups = sync.Pool{New: func() interface{} { return bls12.NewG1() }}
+
+func getG1() *bls12.G1 {
+	return g1Groups.Get().(*bls12.G1)
+}
+
+// Clear sets p to the point at infinity.
+func (p *G1) Clear() {
+	p.p.Zero()
+}
+
+// IsZero returns true if p is the point at infinity.
+func (p *G1) IsZero() bool {
+	g := getG1()
+	defer g1Groups.Put(g)
+	return g.IsZero(&p.p)
+}
+
+// IsEqual returns true if both points are equal.
+func (p *G1) IsEqual(q *G1) bool {
+	g := getG1()
+	defer g1Groups.Put(g)
+	return g.Equal(&p.p, &q.p)
+}
+
+// G1Neg sets z = -x.
+func G1Neg(z, x *G1) {
+	g := getG1()
+	g.Neg(&z.p, &x.p)
+	g1Groups.Put(g)
+}
+
+// G1Add sets z = x + y.
+func G1Add(z, x, y *G1) {
+	g := getG1()
+	g.Add(&z.p, &x.p, &y.p)
+	g1Groups.Put(g)
+}
+
+// G1Sub sets z = x - y.
+func G1Sub(z, x, y *G1) {
+	g := getG1()
+	g.Sub(&z.p, &x.p, &y.p)
+	g1Groups.Put(g)
+}
+
+// G1Mul sets z = s * x. The multiplication uses the endomorphism of the
+// curve, so x must be in G1.
+func G1Mul(z, x *G1, s *Fr) {
+	g := getG1()
+	g.MulScalar(&z.p, &x.p, &s.v)
+	g1Groups.Put(g)
+}
+
+// Serialize returns the compressed encoding of p defined by ZCash: the
+// big-endian x coordinate with the compression flag in the most significant
+// bit, then the infinity flag, then the sign of y.
+func (p *G1) Serialize() []byte {
+	g := getG1()
+	defer g1Groups.Put(g)
+	q := p.p
+	return g.ToCompressed(&q)
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the encoding does not represent a point of G1: points of the curve outside
+// the subgroup of order r are rejected too.
+func (p *G1) Deserialize(buff []byte) error {
+	if len(buff) != G1Size {
+		return errors.New("bls12381: wrong size for G1")
+	}
+	g := getG1()
+	defer g1Groups.Put(g)
+	q, err := g.FromCompressed(buff)
+	if err != nil {
+		return err
+	}
+	p.p = *q
+	return nil
+}
+
+// GetString returns "0" for the point at infinity and "1 x y" with the affine
+// coordinates otherwise, like mcl does.
+func (p *G1) GetString(base int) string {
+	g := getG1()
+	defer g1Groups.Put(g)
+	if g.IsZero(&p.p) {
+		return "0"
+	}
+	q := p.p
+	buff := g.ToBytes(&q)
+	x := new(big.Int).SetBytes(buff[:fpSize])
+	y := new(big.Int).SetBytes(buff[fpSize:])
+	return fmt.Sprintf("1 %s %s", x.Text(base), y.Text(base))
+}
+
+// SetString reads a point from the format returned by GetString. It returns
+// an error if the point is not on the curve. The point does not need to be in
+// G1.
+func (p *G1) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) == 1 && parts[0] == "0" {
+		p.Clear()
+		return nil
+	}
+	if len(parts) != 3 || parts[0] != "1" {
+		return errors.New("bls12381: invalid G1 string")
+	}
+	buff, err := fpStrings(parts[1:], base)
+	if err != nil {
+		return err
+	}
+	g := getG1()
+	defer g1Groups.Put(g)
+	q, err := g.FromBytes(buff)
+	if err != nil {
+		return err
+	}
+	p.p = *q
+	return nil
+}
+
+// fpStrings returns the concatenation of the big-endian encodings of the
+// field elements given as strings. The result is never all zeros, which
+// kilic/bls12-381 reads as the point at infinity.
+func fpStrings(coords []string, base int) ([]byte, error) {
+	buff := make([]byte, len(coords)*fpSize)
+	zero := true
+	for i, c := range coords {
+		b, ok := new(big.Int).SetString(c, base)
+		if !ok || b.Sign() < 0 || b.Cmp(modulus) >= 0 {
+			return nil, errors.New("bls12381: invalid field element")
+		}
+		zero = zero && b.Sign() == 0
+		be := b.Bytes()
+		copy(buff[(i+1)*fpSize-len(be):], be)
+	}
+	if zero {
+		return nil, errors.New("bls12381: point not on curve")
+	}
+	return buff, nil
+}
@@ -0,0 +1,146 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	bls12 "github.com/kilic/bls12-381"
+)
+
+// G2Size is the size in bytes of a serialized G2 point.
+const G2Size = 96
+
+// G2 is a point on the twist y^2 = x^3 + 4(1 + i) over Fp2 = Fp[i] / (i^2 +
+// 1). The zero value is the point at infinity.
+type G2 struct {
+	p bls12.PointG2
+}
+
+var g2Groups = sync.Pool{New: func() interface{} { return bls12.NewG2() }}
+
+func getG2() *bls12.G2 {
+	return g2Groups.Get().(*bls12.G2)
+}
+
+// Clear sets p to the point at infinity.
+func (p *G2) Clear() {
+	p.p.Zero()
+}
+
+// IsZero returns true if p is the point at infinity.
+func (p *G2) IsZero() bool {
+	g := getG2()
+	defer g2Groups.Put(g)
+	return g.IsZero(&p.p)
+}
+
+// IsEqual returns true if both points are equal.
+func (p *G2) IsEqual(q *G2) bool {
+	g := getG2()
+	defer g2Groups.Put(g)
+	return g.Equal(&p.p, &q.p)
+}
+
+// G2Neg sets z = -x.
+func G2Neg(z, x *G2) {
+	g := getG2()
+	g.Neg(&z.p, &x.p)
+	g2Groups.Put(g)
+}
+
+// G2Add sets z = x + y.
+func G2Add(z, x, y *G2) {
+	g := getG2()
+	g.Add(&z.p, &x.p, &y.p)
+	g2Groups.Put(g)
+}
+
+// G2Sub sets z = x - y.
+func G2Sub(z, x, y *G2) {
+	g := getG2()
+	g.Sub(&z.p, &x.p, &y.p)
+	g2Groups.Put(g)
+}
+
+// G2Mul sets z = s * x. The multiplication uses the endomorphism of the
+// twist, so x must be in G2.
+func G2Mul(z, x *G2, s *Fr) {
+	g := getG2()
+	g.MulScalar(&z.p, &x.p, &s.v)
+	g2Groups.Put(g)
+}
+
+// Serialize returns the compressed encoding of p defined by ZCash: the x
+// coordinate as the big-endian c1 then c0 with the flags of G1 in the first
+// byte.
+func (p *G2) Serialize() []byte {
+	g := getG2()
+	defer g2Groups.Put(g)
+	q := p.p
+	return g.ToCompressed(&q)
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the encoding does not represent a point of G2: points of the twist outside
+// the subgroup of order r are rejected too.
+func (p *G2) Deserialize(buff []byte) error {
+	if len(buff) != G2Size {
+		return errors.New("bls12381: wrong size for G2")
+	}
+	g := getG2()
+	defer g2Groups.Put(g)
+	q, err := g.FromCompressed(buff)
+	if err != nil {
+		return err
+	}
+	p.p = *q
+	return nil
+}
+
+// GetString returns "0" for the point at infinity and "1 x.a x.b y.a y.b"
+// with the affine coordinates x = x.a + x.b i and y = y.a + y.b i otherwise,
+// like mcl does.
+func (p *G2) GetString(base int) string {
+	g := getG2()
+	defer g2Groups.Put(g)
+	if g.IsZero(&p.p) {
+		return "0"
+	}
+	q := p.p
+	buff := g.ToBytes(&q)
+	var c [4]string
+	for i := range c {
+		c[i] = new(big.Int).SetBytes(buff[i*fpSize : (i+1)*fpSize]).Text(base)
+	}
+	// kilic/bls12-381 writes c1 before c0
+	return fmt.Sprintf("1 %s %s %s %s", c[1], c[0], c[3], c[2])
+}
+
+// SetString reads a point from the format returned by GetString. It returns
+// an error if the point is not on the twist. The point does not need to be
+// in G2.
+func (p *G2) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) == 1 && parts[0] == "0" {
+		p.Clear()
+		return nil
+	}
+	if len(parts) != 5 || parts[0] != "1" {
+		return errors.New("bls12381: invalid G2 string")
+	}
+	buff, err := fpStrings([]string{parts[2], parts[1], parts[4], parts[3]}, base)
+	if err != nil {
+		return err
+	}
+	g := getG2()
+	defer g2Groups.Put(g)
+	q, err := g.FromBytes(buff)
+	if err != nil {
+		return err
+	}
+	p.p = *q
+	return nil
+}
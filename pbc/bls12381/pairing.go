@@ -0,0 +1,165 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	bls12 "github.com/kilic/bls12-381"
+)
+
+// GTSize is the size in bytes of a serialized GT element.
+const GTSize = 12 * fpSize
+
+// GT is an element of the target group, a subgroup of Fp12*. The zero value is
+// NOT a valid element, use SetInt64(1) to get the identity.
+type GT struct {
+	f bls12.E
+}
+
+var gtGroups = sync.Pool{New: func() interface{} { return bls12.NewGT() }}
+
+var engines = sync.Pool{New: func() interface{} { return bls12.NewEngine() }}
+
+// gtOne is the identity of GT.
+var gtOne = *bls12.NewGT().New()
+
+// Clear sets x to zero, which is not an element of GT.
+func (x *GT) Clear() {
+	x.f = bls12.E{}
+}
+
+// SetInt64 sets x to the constant v.
+func (x *GT) SetInt64(v int64) {
+	switch v {
+	case 0:
+		x.Clear()
+	case 1:
+		x.f = gtOne
+	default:
+		// the constant is the last coordinate of the encoding
+		c := new(big.Int).Mod(big.NewInt(v), modulus).Bytes()
+		buff := make([]byte, GTSize)
+		copy(buff[GTSize-len(c):], c)
+		if err := x.Deserialize(buff); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// IsEqual returns true if both elements are equal.
+func (x *GT) IsEqual(y *GT) bool {
+	return x.f.Equal(&y.f)
+}
+
+// IsOne returns true if x is the identity of GT.
+func (x *GT) IsOne() bool {
+	return x.f.IsOne()
+}
+
+// Serialize returns the concatenation of the twelve big-endian coordinates of
+// x, from the coefficient of the highest power of the generators of the tower
+// of extensions down to the constant.
+func (x *GT) Serialize() []byte {
+	g := gtGroups.Get().(*bls12.GT)
+	defer gtGroups.Put(g)
+	return g.ToBytes(&x.f)
+}
+
+// Deserialize reads an encoding produced by Serialize. It only checks that
+// every coordinate is reduced.
+func (x *GT) Deserialize(buff []byte) error {
+	if len(buff) != GTSize {
+		return errors.New("bls12381: wrong size for GT")
+	}
+	g := gtGroups.Get().(*bls12.GT)
+	defer gtGroups.Put(g)
+	// the element is returned along with an error when it is not in the
+	// subgroup, which is left to the caller to check
+	f, err := g.FromBytes(buff)
+	if f == nil {
+		return err
+	}
+	x.f = *f
+	return nil
+}
+
+// GetString returns the twelve coordinates separated by spaces, in mcl's
+// order: from the constant up to the coefficient of the highest power.
+func (x *GT) GetString(base int) string {
+	buff := x.Serialize()
+	s := make([]string, 12)
+	for i := range s {
+		c := buff[(11-i)*fpSize : (12-i)*fpSize]
+		s[i] = new(big.Int).SetBytes(c).Text(base)
+	}
+	return strings.Join(s, " ")
+}
+
+// SetString reads the format returned by GetString.
+func (x *GT) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) != 12 {
+		return errors.New("bls12381: invalid GT string")
+	}
+	buff := make([]byte, GTSize)
+	for i, p := range parts {
+		b, ok := new(big.Int).SetString(p, base)
+		if !ok || b.Sign() < 0 || b.Cmp(modulus) >= 0 {
+			return errors.New("bls12381: invalid field element")
+		}
+		be := b.Bytes()
+		copy(buff[(12-i)*fpSize-len(be):], be)
+	}
+	return x.Deserialize(buff)
+}
+
+// GTMul sets z = x * y.
+func GTMul(z, x, y *GT) {
+	g := gtGroups.Get().(*bls12.GT)
+	g.Mul(&z.f, &x.f, &y.f)
+	gtGroups.Put(g)
+}
+
+// GTDiv sets z = x / y.
+func GTDiv(z, x, y *GT) {
+	g := gtGroups.Get().(*bls12.GT)
+	var t bls12.E
+	g.Inverse(&t, &y.f)
+	g.Mul(&z.f, &x.f, &t)
+	gtGroups.Put(g)
+}
+
+// GTInv sets z = 1 / x.
+func GTInv(z, x *GT) {
+	g := gtGroups.Get().(*bls12.GT)
+	g.Inverse(&z.f, &x.f)
+	gtGroups.Put(g)
+}
+
+// GTPow sets z = x^s. The exponentiation uses cyclotomic squarings, so x must
+// be in GT.
+func GTPow(z, x *GT, s *Fr) {
+	g := gtGroups.Get().(*bls12.GT)
+	g.Exp(&z.f, &x.f, s.Big())
+	gtGroups.Put(g)
+}
+
+// Pairing sets z = e(x, y), the optimal ate pairing of x and y.
+func Pairing(z *GT, x *G1, y *G2) {
+	PairingProduct(z, []*G1{x}, []*G2{y})
+}
+
+// PairingProduct sets z to the product of the pairings e(ps[i], qs[i]),
+// sharing a single final exponentiation between all the Miller loops.
+func PairingProduct(z *GT, ps []*G1, qs []*G2) {
+	e := engines.Get().(*bls12.Engine)
+	defer engines.Put(e)
+	for i := range ps {
+		// AddPair normalizes the points it is given
+		p, q := ps[i].p, qs[i].p
+		e.AddPair(&p, &q)
+	}
+	z.f = *e.Result()
+}
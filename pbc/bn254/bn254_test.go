@@ -0,0 +1,218 @@
+package bn254
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mapToG1Vector is HashAndMapTo("Fp254_G1_Base_Seed").GetString(16) computed
+// with mcl, see pbc.Fp254_G1_Base_Str.
+const mapToG1Vector = "1 1fa44dfba809dbb8b3f0b65cad50ef07e156e72e5018330db1c8168428753433 1cf8abd31ad2251d886285658874a29283afb1ed9681cc351bcaeb5dc4b25eb5"
+
+func randFp(t *testing.T) (fp, *big.Int) {
+	b, err := rand.Int(rand.Reader, fpField.mod)
+	require.Nil(t, err)
+	var x fp
+	x.setBig(b)
+	return x, b
+}
+
+func randFr(t *testing.T) *Fr {
+	b, err := rand.Int(rand.Reader, frField.mod)
+	require.Nil(t, err)
+	var x Fr
+	x.SetBig(b)
+	return &x
+}
+
+func randG1(t *testing.T) *G1 {
+	var p G1
+	require.Nil(t, p.HashAndMapTo(randBytes(t)))
+	return &p
+}
+
+func randG2(t *testing.T) *G2 {
+	var p G2
+	require.Nil(t, p.HashAndMapTo(randBytes(t)))
+	return &p
+}
+
+func randBytes(t *testing.T) []byte {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	require.Nil(t, err)
+	return b
+}
+
+func TestFpArithmetic(t *testing.T) {
+	p := fpField.mod
+	for i := 0; i < 100; i++ {
+		x, xb := randFp(t)
+		y, yb := randFp(t)
+		var z fp
+		exp := new(big.Int)
+
+		z.add(&x, &y)
+		exp.Add(xb, yb).Mod(exp, p)
+		require.Equal(t, exp, z.big())
+
+		z.sub(&x, &y)
+		exp.Sub(xb, yb).Mod(exp, p)
+		require.Equal(t, exp, z.big())
+
+		z.mul(&x, &y)
+		exp.Mul(xb, yb).Mod(exp, p)
+		require.Equal(t, exp, z.big())
+
+		z.inv(&x)
+		exp.ModInverse(xb, p)
+		require.Equal(t, exp, z.big())
+
+		z.sqr(&x)
+		var r fp
+		require.True(t, r.sqrt(&z))
+		r.sqr(&r)
+		require.True(t, r.equal(&z))
+
+		buff := make([]byte, 32)
+		x.bytes(buff)
+		var x2 fp
+		require.Nil(t, x2.setBytes(buff))
+		require.True(t, x.equal(&x2))
+	}
+}
+
+func TestFp2Sqrt(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		a, _ := randFp(t)
+		b, _ := randFp(t)
+		x := fp2{a, b}
+		var s, r fp2
+		s.sqr(&x)
+		require.True(t, r.sqrt(&s))
+		r.sqr(&r)
+		require.True(t, r.equal(&s))
+	}
+}
+
+func TestFp12Frobenius(t *testing.T) {
+	var g GT
+	for _, c := range g.coeffs() {
+		*c, _ = randFp(t)
+	}
+	x := g.f
+	var f, e fp12
+	f.frobenius(&x)
+	e.exp(&x, fpField.mod)
+	require.True(t, f.equal(&e))
+
+	var i fp12
+	i.inv(&x)
+	i.mul(&i, &x)
+	require.True(t, i.isOne())
+}
+
+func TestHashAndMapTo(t *testing.T) {
+	var p G1
+	require.Nil(t, p.HashAndMapTo([]byte("Fp254_G1_Base_Seed")))
+	require.Equal(t, mapToG1Vector, p.GetString(16))
+
+	var q G2
+	require.Nil(t, q.HashAndMapTo([]byte("Fp254_G2_Base_Seed")))
+	require.True(t, q.IsOnCurve())
+	require.True(t, g2InSubgroup(&q))
+}
+
+func TestG1(t *testing.T) {
+	p := randG1(t)
+	require.True(t, p.IsOnCurve())
+	var z G1
+	g1MulBig(&z, p, frField.mod)
+	require.True(t, z.IsZero())
+
+	a, b := randFr(t), randFr(t)
+	var ab Fr
+	FrAdd(&ab, a, b)
+	var pa, pb, pab G1
+	G1Mul(&pa, p, a)
+	G1Mul(&pb, p, b)
+	G1Mul(&pab, p, &ab)
+	G1Add(&pa, &pa, &pb)
+	require.True(t, pa.IsEqual(&pab))
+	G1Sub(&pa, &pa, &pb)
+	G1Mul(&pb, p, a)
+	require.True(t, pa.IsEqual(&pb))
+
+	var q G1
+	require.Nil(t, q.Deserialize(pab.Serialize()))
+	require.True(t, q.IsEqual(&pab))
+	require.Nil(t, q.SetString(pab.GetString(16), 16))
+	require.True(t, q.IsEqual(&pab))
+	q.Clear()
+	require.Nil(t, q.Deserialize(z.Serialize()))
+	require.True(t, q.IsZero())
+	require.NotNil(t, q.Deserialize(nil))
+}
+
+func TestG2(t *testing.T) {
+	p := randG2(t)
+	require.True(t, p.IsOnCurve())
+	require.True(t, g2InSubgroup(p))
+
+	a, b := randFr(t), randFr(t)
+	var ab Fr
+	FrAdd(&ab, a, b)
+	var pa, pb, pab G2
+	G2Mul(&pa, p, a)
+	G2Mul(&pb, p, b)
+	G2Mul(&pab, p, &ab)
+	G2Add(&pa, &pa, &pb)
+	require.True(t, pa.IsEqual(&pab))
+
+	var q G2
+	require.Nil(t, q.Deserialize(pab.Serialize()))
+	require.True(t, q.IsEqual(&pab))
+	require.Nil(t, q.SetString(pab.GetString(16), 16))
+	require.True(t, q.IsEqual(&pab))
+	require.NotNil(t, q.Deserialize(nil))
+}
+
+func TestPairing(t *testing.T) {
+	p, q := randG1(t), randG2(t)
+	a, b := randFr(t), randFr(t)
+
+	var e GT
+	Pairing(&e, p, q)
+	require.False(t, e.IsOne())
+	var er GT
+	er.f.exp(&e.f, frField.mod)
+	require.True(t, er.IsOne())
+
+	var pa G1
+	var qb G2
+	G1Mul(&pa, p, a)
+	G2Mul(&qb, q, b)
+	var e1, e2 GT
+	Pairing(&e1, &pa, &qb)
+	var ab Fr
+	FrMul(&ab, a, b)
+	GTPow(&e2, &e, &ab)
+	require.True(t, e1.IsEqual(&e2))
+
+	var e3 GT
+	require.Nil(t, e3.Deserialize(e1.Serialize()))
+	require.True(t, e3.IsEqual(&e1))
+	require.Nil(t, e3.SetString(e1.GetString(16), 16))
+	require.True(t, e3.IsEqual(&e1))
+
+	var one GT
+	GTDiv(&one, &e1, &e2)
+	require.True(t, one.IsOne())
+
+	var z G1
+	Pairing(&e, &z, q)
+	require.True(t, e.IsOne())
+}
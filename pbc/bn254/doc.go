@@ -0,0 +1,8 @@
+// Package bn254 is a pure Go implementation of the pairing over the BN254
+// curve used by mcl (CurveFp254BNb). Its API mirrors the subset of the
+// go-dfinity-crypto/bls bindings used by the pbc package, and every
+// serialization is byte-for-byte identical to mcl's, so that both backends can
+// be used interchangeably.
+//
+// This implementation is not constant time.
+package bn254
@@ -0,0 +1,242 @@
+package bn254
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// field holds the constants needed to do Montgomery arithmetic modulo a prime
+// of at most 256 bits. Elements are represented as four little-endian 64-bit
+// words in Montgomery form (x * 2^256 mod m).
+type field struct {
+	m     [4]uint64 // modulus
+	minv  uint64    // -m^-1 mod 2^64
+	r2    [4]uint64 // 2^512 mod m
+	one   [4]uint64 // 2^256 mod m
+	bits  int       // bit length of the modulus
+	size  int       // byte length of a serialized element
+	mod   *big.Int
+	pm2   []uint64 // m - 2, for inversion
+	pp1d4 []uint64 // (m + 1) / 4, for the square root
+	pm1d2 []uint64 // (m - 1) / 2, for the legendre symbol
+}
+
+// errTooLarge is returned when decoding a value which is not reduced modulo the
+// field's modulus.
+var errTooLarge = errors.New("bn254: value is not reduced")
+
+func newField(modulus string) *field {
+	m, ok := new(big.Int).SetString(modulus, 16)
+	if !ok {
+		panic("bn254: invalid modulus")
+	}
+	f := &field{mod: m, bits: m.BitLen(), size: 32}
+	f.m = bigToWords(m)
+
+	// -m^-1 mod 2^64 by Newton iteration
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.m[0]*inv
+	}
+	f.minv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	f.one = bigToWords(new(big.Int).Mod(r, m))
+	r2 := new(big.Int).Mul(r, r)
+	f.r2 = bigToWords(r2.Mod(r2, m))
+
+	one := big.NewInt(1)
+	f.pm2 = bigWords(new(big.Int).Sub(m, big.NewInt(2)))
+	f.pp1d4 = bigWords(new(big.Int).Rsh(new(big.Int).Add(m, one), 2))
+	f.pm1d2 = bigWords(new(big.Int).Rsh(new(big.Int).Sub(m, one), 1))
+	return f
+}
+
+// bigWords returns the little-endian 64-bit words of a positive integer.
+func bigWords(b *big.Int) []uint64 {
+	n := (b.BitLen() + 63) / 64
+	out := make([]uint64, n)
+	bytes := b.Bytes()
+	for i := 0; i < len(bytes); i++ {
+		v := uint64(bytes[len(bytes)-1-i])
+		out[i/8] |= v << (8 * uint(i%8))
+	}
+	return out
+}
+
+func bigToWords(b *big.Int) [4]uint64 {
+	var out [4]uint64
+	copy(out[:], bigWords(b))
+	return out
+}
+
+func wordsToBig(w *[4]uint64) *big.Int {
+	var buff [32]byte
+	for i := 0; i < 32; i++ {
+		buff[31-i] = byte(w[i/8] >> (8 * uint(i%8)))
+	}
+	return new(big.Int).SetBytes(buff[:])
+}
+
+// madd returns the double word a + b*c + d.
+func madd(a, b, c, d uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(b, c)
+	lo, carry = bits.Add64(lo, a, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return
+}
+
+// mul sets z = x * y * 2^-256 mod m using the CIOS method.
+func (f *field) mul(z, x, y *[4]uint64) {
+	var t [6]uint64
+	var c, hi uint64
+	for i := 0; i < 4; i++ {
+		c = 0
+		for j := 0; j < 4; j++ {
+			c, t[j] = madd(t[j], x[j], y[i], c)
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] = c
+
+		m := t[0] * f.minv
+		c, _ = madd(t[0], m, f.m[0], 0)
+		for j := 1; j < 4; j++ {
+			c, t[j-1] = madd(t[j], m, f.m[j], c)
+		}
+		t[3], hi = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + hi
+	}
+	f.reduce(z, &t)
+}
+
+// reduce sets z = t - m if t >= m, z = t otherwise, in constant time. t is
+// expected to be smaller than 2m.
+func (f *field) reduce(z *[4]uint64, t *[6]uint64) {
+	var s [4]uint64
+	var b uint64
+	s[0], b = bits.Sub64(t[0], f.m[0], 0)
+	s[1], b = bits.Sub64(t[1], f.m[1], b)
+	s[2], b = bits.Sub64(t[2], f.m[2], b)
+	s[3], b = bits.Sub64(t[3], f.m[3], b)
+	_, b = bits.Sub64(t[4], 0, b)
+	// b == 1 means t < m, keep t
+	mask := -b
+	for i := 0; i < 4; i++ {
+		z[i] = (t[i] & mask) | (s[i] &^ mask)
+	}
+}
+
+func (f *field) add(z, x, y *[4]uint64) {
+	var t [6]uint64
+	var c uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	t[4] = c
+	f.reduce(z, &t)
+}
+
+func (f *field) sub(z, x, y *[4]uint64) {
+	var t [4]uint64
+	var b uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+	// add back the modulus if it underflowed
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(t[0], f.m[0]&mask, 0)
+	z[1], c = bits.Add64(t[1], f.m[1]&mask, c)
+	z[2], c = bits.Add64(t[2], f.m[2]&mask, c)
+	z[3], _ = bits.Add64(t[3], f.m[3]&mask, c)
+}
+
+func (f *field) neg(z, x *[4]uint64) {
+	var zero [4]uint64
+	f.sub(z, &zero, x)
+}
+
+// exp sets z = x^e where e is a public exponent given as little-endian words.
+func (f *field) exp(z, x *[4]uint64, e []uint64) {
+	res := f.one
+	base := *x
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			f.mul(&res, &res, &res)
+			if (e[i]>>uint(j))&1 == 1 {
+				f.mul(&res, &res, &base)
+			}
+		}
+	}
+	*z = res
+}
+
+func (f *field) inv(z, x *[4]uint64) {
+	f.exp(z, x, f.pm2)
+}
+
+func isZeroWords(x *[4]uint64) bool {
+	return x[0]|x[1]|x[2]|x[3] == 0
+}
+
+// toMont converts a canonical value into Montgomery form.
+func (f *field) toMont(z, x *[4]uint64) {
+	f.mul(z, x, &f.r2)
+}
+
+// fromMont converts a value in Montgomery form into its canonical value.
+func (f *field) fromMont(z, x *[4]uint64) {
+	one := [4]uint64{1}
+	f.mul(z, x, &one)
+}
+
+// isReduced returns true if the canonical value x is smaller than the modulus.
+func (f *field) isReduced(x *[4]uint64) bool {
+	var b uint64
+	_, b = bits.Sub64(x[0], f.m[0], 0)
+	_, b = bits.Sub64(x[1], f.m[1], b)
+	_, b = bits.Sub64(x[2], f.m[2], b)
+	_, b = bits.Sub64(x[3], f.m[3], b)
+	return b == 1
+}
+
+// setBytes reads a little-endian encoded canonical value and converts it in
+// Montgomery form. It returns an error if the value is not reduced.
+func (f *field) setBytes(z *[4]uint64, buff []byte) error {
+	var w [4]uint64
+	for i := 0; i < len(buff) && i < 32; i++ {
+		w[i/8] |= uint64(buff[i]) << (8 * uint(i%8))
+	}
+	if !f.isReduced(&w) {
+		return errTooLarge
+	}
+	f.toMont(z, &w)
+	return nil
+}
+
+// bytes writes the little-endian canonical value of x into buff.
+func (f *field) bytes(buff []byte, x *[4]uint64) {
+	var w [4]uint64
+	f.fromMont(&w, x)
+	for i := 0; i < len(buff) && i < 32; i++ {
+		buff[i] = byte(w[i/8] >> (8 * uint(i%8)))
+	}
+}
+
+func (f *field) toBig(x *[4]uint64) *big.Int {
+	var w [4]uint64
+	f.fromMont(&w, x)
+	return wordsToBig(&w)
+}
+
+func (f *field) setBig(z *[4]uint64, b *big.Int) {
+	r := new(big.Int).Mod(b, f.mod)
+	w := bigToWords(r)
+	f.toMont(z, &w)
+}
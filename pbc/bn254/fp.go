@@ -0,0 +1,148 @@
+package bn254
+
+import "math/big"
+
+// fpField is the base field of the curve.
+var fpField = newField("2523648240000001ba344d80000000086121000000000013a700000000000013")
+
+// fp is an element of the base field in Montgomery form.
+type fp [4]uint64
+
+func fw(x *fp) *[4]uint64 {
+	return (*[4]uint64)(x)
+}
+
+func (z *fp) add(x, y *fp) *fp {
+	fpField.add(fw(z), fw(x), fw(y))
+	return z
+}
+
+func (z *fp) sub(x, y *fp) *fp {
+	fpField.sub(fw(z), fw(x), fw(y))
+	return z
+}
+
+func (z *fp) neg(x *fp) *fp {
+	fpField.neg(fw(z), fw(x))
+	return z
+}
+
+func (z *fp) mul(x, y *fp) *fp {
+	fpField.mul(fw(z), fw(x), fw(y))
+	return z
+}
+
+func (z *fp) sqr(x *fp) *fp {
+	fpField.mul(fw(z), fw(x), fw(x))
+	return z
+}
+
+func (z *fp) inv(x *fp) *fp {
+	fpField.inv(fw(z), fw(x))
+	return z
+}
+
+func (z *fp) exp(x *fp, e []uint64) *fp {
+	fpField.exp(fw(z), fw(x), e)
+	return z
+}
+
+func (z *fp) set(x *fp) *fp {
+	*z = *x
+	return z
+}
+
+func (z *fp) setZero() *fp {
+	*z = fp{}
+	return z
+}
+
+func (z *fp) setOne() *fp {
+	*z = fp(fpField.one)
+	return z
+}
+
+func (z *fp) setInt64(v int64) *fp {
+	fpField.setBig(fw(z), big.NewInt(v))
+	return z
+}
+
+func (z *fp) setBig(b *big.Int) *fp {
+	fpField.setBig(fw(z), b)
+	return z
+}
+
+func (z *fp) big() *big.Int {
+	return fpField.toBig(fw(z))
+}
+
+func (z *fp) isZero() bool {
+	return isZeroWords(fw(z))
+}
+
+func (z *fp) isOne() bool {
+	return *z == fp(fpField.one)
+}
+
+func (z *fp) equal(x *fp) bool {
+	return *z == *x
+}
+
+// isOdd returns true if the canonical value of z is odd.
+func (z *fp) isOdd() bool {
+	var c [4]uint64
+	fpField.fromMont(&c, fw(z))
+	return c[0]&1 == 1
+}
+
+// legendre returns 1 if z is a non-zero square, -1 if it is a non-square and 0
+// if it is zero.
+func (z *fp) legendre() int {
+	var t fp
+	t.exp(z, fpField.pm1d2)
+	switch {
+	case t.isZero():
+		return 0
+	case t.isOne():
+		return 1
+	default:
+		return -1
+	}
+}
+
+// sqrt sets z to a square root of x and returns true if x is a square. Since
+// p = 3 mod 4, the root is x^((p+1)/4).
+func (z *fp) sqrt(x *fp) bool {
+	var r, c fp
+	r.exp(x, fpField.pp1d4)
+	c.sqr(&r)
+	if !c.equal(x) {
+		return false
+	}
+	*z = r
+	return true
+}
+
+// halve sets z = x / 2.
+func (z *fp) halve(x *fp) *fp {
+	return z.mul(x, &fpHalf)
+}
+
+func (z *fp) setBytes(buff []byte) error {
+	return fpField.setBytes(fw(z), buff)
+}
+
+func (z *fp) bytes(buff []byte) {
+	fpField.bytes(buff, fw(z))
+}
+
+func (z *fp) String() string {
+	return z.big().Text(16)
+}
+
+var fpHalf fp
+
+func init() {
+	fpHalf.setInt64(2)
+	fpHalf.inv(&fpHalf)
+}
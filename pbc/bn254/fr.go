@@ -0,0 +1,156 @@
+package bn254
+
+import (
+	"errors"
+	"math/big"
+)
+
+// frField is the scalar field of the curve, i.e. integers modulo the order of
+// G1, G2 and GT.
+var frField = newField("2523648240000001ba344d8000000007ff9f800000000010a10000000000000d")
+
+// Fr is an element of the scalar field. The zero value is the scalar 0.
+type Fr struct {
+	v [4]uint64 // Montgomery form
+}
+
+// FrSize is the size in bytes of a serialized Fr.
+const FrSize = 32
+
+// Order returns the order of the groups G1, G2 and GT.
+func Order() *big.Int {
+	return new(big.Int).Set(frField.mod)
+}
+
+// FieldModulus returns the characteristic of the base field.
+func FieldModulus() *big.Int {
+	return new(big.Int).Set(fpField.mod)
+}
+
+// Clear sets x to zero.
+func (x *Fr) Clear() {
+	x.v = [4]uint64{}
+}
+
+// SetInt64 sets x to v modulo the order.
+func (x *Fr) SetInt64(v int64) {
+	frField.setBig(&x.v, big.NewInt(v))
+}
+
+// SetBig sets x to b modulo the order.
+func (x *Fr) SetBig(b *big.Int) {
+	frField.setBig(&x.v, b)
+}
+
+// Big returns the canonical value of x.
+func (x *Fr) Big() *big.Int {
+	return frField.toBig(&x.v)
+}
+
+// IsEqual returns true if both scalars are equal.
+func (x *Fr) IsEqual(y *Fr) bool {
+	return x.v == y.v
+}
+
+// IsZero returns true if x is zero.
+func (x *Fr) IsZero() bool {
+	return isZeroWords(&x.v)
+}
+
+// IsOne returns true if x is one.
+func (x *Fr) IsOne() bool {
+	return x.v == frField.one
+}
+
+// Serialize returns the little-endian canonical encoding of x on FrSize bytes.
+func (x *Fr) Serialize() []byte {
+	buff := make([]byte, FrSize)
+	frField.bytes(buff, &x.v)
+	return buff
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the size is wrong or if the value is not reduced modulo the order.
+func (x *Fr) Deserialize(buff []byte) error {
+	if len(buff) != FrSize {
+		return errors.New("bn254: wrong size for Fr")
+	}
+	return frField.setBytes(&x.v, buff)
+}
+
+// SetLittleEndian reads the little-endian buffer and keeps only the bits
+// strictly below the bit length of the order, as mcl does, so the value is
+// always reduced.
+func (x *Fr) SetLittleEndian(buff []byte) error {
+	x.v = maskedWords(buff, frField.bits-1)
+	frField.toMont(&x.v, &x.v)
+	return nil
+}
+
+// maskedWords reads a little-endian buffer into words and keeps only the
+// lowest n bits.
+func maskedWords(buff []byte, n int) [4]uint64 {
+	var w [4]uint64
+	for i := 0; i < len(buff) && i < 32; i++ {
+		w[i/8] |= uint64(buff[i]) << (8 * uint(i%8))
+	}
+	for i := 0; i < 4; i++ {
+		switch {
+		case n >= 64*(i+1):
+			continue
+		case n <= 64*i:
+			w[i] = 0
+		default:
+			w[i] &= (uint64(1) << uint(n-64*i)) - 1
+		}
+	}
+	return w
+}
+
+// GetString returns the value of x in the given base (10 or 16).
+func (x *Fr) GetString(base int) string {
+	return x.Big().Text(base)
+}
+
+// SetString reads a value in the given base. It returns an error if the value
+// is not reduced.
+func (x *Fr) SetString(s string, base int) error {
+	b, ok := new(big.Int).SetString(s, base)
+	if !ok || b.Sign() < 0 || b.Cmp(frField.mod) >= 0 {
+		return errors.New("bn254: invalid Fr string")
+	}
+	frField.setBig(&x.v, b)
+	return nil
+}
+
+// FrAdd sets z = x + y.
+func FrAdd(z, x, y *Fr) {
+	frField.add(&z.v, &x.v, &y.v)
+}
+
+// FrSub sets z = x - y.
+func FrSub(z, x, y *Fr) {
+	frField.sub(&z.v, &x.v, &y.v)
+}
+
+// FrMul sets z = x * y.
+func FrMul(z, x, y *Fr) {
+	frField.mul(&z.v, &x.v, &y.v)
+}
+
+// FrNeg sets z = -x.
+func FrNeg(z, x *Fr) {
+	frField.neg(&z.v, &x.v)
+}
+
+// FrInv sets z = 1 / x. The inverse of zero is zero.
+func FrInv(z, x *Fr) {
+	frField.inv(&z.v, &x.v)
+}
+
+// FrDiv sets z = x / y.
+func FrDiv(z, x, y *Fr) {
+	var t Fr
+	FrInv(&t, y)
+	FrMul(z, x, &t)
+}
@@ -0,0 +1,327 @@
+package bn254
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// G1Size is the size in bytes of a serialized G1 point.
+const G1Size = 32
+
+// curveB is the constant of the curve y^2 = x^3 + b.
+var curveB fp
+
+func init() {
+	curveB.setInt64(2)
+}
+
+// G1 is a point on the curve y^2 = x^3 + 2 over Fp, in Jacobian coordinates.
+// The zero value is the point at infinity.
+type G1 struct {
+	x, y, z fp
+}
+
+// Clear sets p to the point at infinity.
+func (p *G1) Clear() {
+	*p = G1{}
+}
+
+// IsZero returns true if p is the point at infinity.
+func (p *G1) IsZero() bool {
+	return p.z.isZero()
+}
+
+// IsEqual returns true if both points are equal.
+func (p *G1) IsEqual(q *G1) bool {
+	if p.IsZero() || q.IsZero() {
+		return p.IsZero() && q.IsZero()
+	}
+	var z1, z2, t1, t2 fp
+	z1.sqr(&p.z)
+	z2.sqr(&q.z)
+	t1.mul(&p.x, &z2)
+	t2.mul(&q.x, &z1)
+	if !t1.equal(&t2) {
+		return false
+	}
+	z1.mul(&z1, &p.z)
+	z2.mul(&z2, &q.z)
+	t1.mul(&p.y, &z2)
+	t2.mul(&q.y, &z1)
+	return t1.equal(&t2)
+}
+
+// IsOnCurve returns true if p satisfies the curve equation.
+func (p *G1) IsOnCurve() bool {
+	if p.IsZero() {
+		return true
+	}
+	var a G1
+	a.set(p)
+	a.normalize()
+	var l, r fp
+	l.sqr(&a.y)
+	r.sqr(&a.x)
+	r.mul(&r, &a.x)
+	r.add(&r, &curveB)
+	return l.equal(&r)
+}
+
+func (p *G1) set(q *G1) {
+	*p = *q
+}
+
+// setAffine sets p to the affine point (x,y).
+func (p *G1) setAffine(x, y *fp) {
+	p.x.set(x)
+	p.y.set(y)
+	p.z.setOne()
+}
+
+// normalize converts p to affine coordinates, i.e. z = 1.
+func (p *G1) normalize() {
+	if p.IsZero() || p.z.isOne() {
+		return
+	}
+	var zi, zi2 fp
+	zi.inv(&p.z)
+	zi2.sqr(&zi)
+	p.x.mul(&p.x, &zi2)
+	zi2.mul(&zi2, &zi)
+	p.y.mul(&p.y, &zi2)
+	p.z.setOne()
+}
+
+// G1Neg sets z = -x.
+func G1Neg(z, x *G1) {
+	z.x.set(&x.x)
+	z.y.neg(&x.y)
+	z.z.set(&x.z)
+}
+
+// G1Dbl sets z = 2x.
+func G1Dbl(z, x *G1) {
+	if x.IsZero() {
+		z.Clear()
+		return
+	}
+	var a, b, c, d, e, f, t fp
+	a.sqr(&x.x)
+	b.sqr(&x.y)
+	c.sqr(&b)
+	d.add(&x.x, &b)
+	d.sqr(&d)
+	d.sub(&d, &a)
+	d.sub(&d, &c)
+	d.add(&d, &d)
+	e.add(&a, &a)
+	e.add(&e, &a)
+	f.sqr(&e)
+
+	var x3, y3, z3 fp
+	x3.sub(&f, &d)
+	x3.sub(&x3, &d)
+	t.add(&c, &c)
+	t.add(&t, &t)
+	t.add(&t, &t)
+	y3.sub(&d, &x3)
+	y3.mul(&y3, &e)
+	y3.sub(&y3, &t)
+	z3.mul(&x.y, &x.z)
+	z3.add(&z3, &z3)
+	z.x, z.y, z.z = x3, y3, z3
+}
+
+// G1Add sets z = x + y.
+func G1Add(z, x, y *G1) {
+	if x.IsZero() {
+		z.set(y)
+		return
+	}
+	if y.IsZero() {
+		z.set(x)
+		return
+	}
+	var z1z1, z2z2, u1, u2, s1, s2, h, i, j, r, v fp
+	z1z1.sqr(&x.z)
+	z2z2.sqr(&y.z)
+	u1.mul(&x.x, &z2z2)
+	u2.mul(&y.x, &z1z1)
+	s1.mul(&x.y, &y.z)
+	s1.mul(&s1, &z2z2)
+	s2.mul(&y.y, &x.z)
+	s2.mul(&s2, &z1z1)
+	h.sub(&u2, &u1)
+	r.sub(&s2, &s1)
+	if h.isZero() {
+		if r.isZero() {
+			G1Dbl(z, x)
+		} else {
+			z.Clear()
+		}
+		return
+	}
+	r.add(&r, &r)
+	i.add(&h, &h)
+	i.sqr(&i)
+	j.mul(&h, &i)
+	v.mul(&u1, &i)
+
+	var x3, y3, z3, t fp
+	x3.sqr(&r)
+	x3.sub(&x3, &j)
+	x3.sub(&x3, &v)
+	x3.sub(&x3, &v)
+	y3.sub(&v, &x3)
+	y3.mul(&y3, &r)
+	t.mul(&s1, &j)
+	t.add(&t, &t)
+	y3.sub(&y3, &t)
+	z3.add(&x.z, &y.z)
+	z3.sqr(&z3)
+	z3.sub(&z3, &z1z1)
+	z3.sub(&z3, &z2z2)
+	z3.mul(&z3, &h)
+	z.x, z.y, z.z = x3, y3, z3
+}
+
+// G1Sub sets z = x - y.
+func G1Sub(z, x, y *G1) {
+	var t G1
+	G1Neg(&t, y)
+	G1Add(z, x, &t)
+}
+
+// G1Mul sets z = s * x.
+func G1Mul(z, x *G1, s *Fr) {
+	g1MulBig(z, x, s.Big())
+}
+
+// g1MulBig sets z = k * x for a non-negative integer k.
+func g1MulBig(z, x *G1, k *big.Int) {
+	var r G1
+	base := *x
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		G1Dbl(&r, &r)
+		if k.Bit(i) == 1 {
+			G1Add(&r, &r, &base)
+		}
+	}
+	*z = r
+}
+
+// Serialize returns the mcl encoding of p: the little-endian x coordinate
+// with the highest bit set if y is odd. The point at infinity is encoded as
+// zeros.
+func (p *G1) Serialize() []byte {
+	buff := make([]byte, G1Size)
+	if p.IsZero() {
+		return buff
+	}
+	var a G1
+	a.set(p)
+	a.normalize()
+	a.x.bytes(buff)
+	if a.y.isOdd() {
+		buff[G1Size-1] |= 0x80
+	}
+	return buff
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the encoding does not represent a point on the curve.
+func (p *G1) Deserialize(buff []byte) error {
+	if len(buff) != G1Size {
+		return errors.New("bn254: wrong size for G1")
+	}
+	if isZeroBytes(buff) {
+		p.Clear()
+		return nil
+	}
+	b := make([]byte, G1Size)
+	copy(b, buff)
+	odd := b[G1Size-1]&0x80 != 0
+	b[G1Size-1] &= 0x7f
+	var x, y fp
+	if err := x.setBytes(b); err != nil {
+		return err
+	}
+	if !g1YFromX(&y, &x, odd) {
+		return errors.New("bn254: G1 point not on curve")
+	}
+	p.setAffine(&x, &y)
+	return nil
+}
+
+// g1YFromX computes y such that (x,y) is on the curve with the given parity.
+func g1YFromX(y, x *fp, odd bool) bool {
+	var t fp
+	t.sqr(x)
+	t.mul(&t, x)
+	t.add(&t, &curveB)
+	if !y.sqrt(&t) {
+		return false
+	}
+	if y.isOdd() != odd {
+		y.neg(y)
+	}
+	return true
+}
+
+// GetString returns "0" for the point at infinity and "1 x y" with the affine
+// coordinates otherwise, like mcl does.
+func (p *G1) GetString(base int) string {
+	if p.IsZero() {
+		return "0"
+	}
+	var a G1
+	a.set(p)
+	a.normalize()
+	return fmt.Sprintf("1 %s %s", a.x.big().Text(base), a.y.big().Text(base))
+}
+
+// SetString reads a point from the format returned by GetString. It returns
+// an error if the point is not on the curve.
+func (p *G1) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) == 1 && parts[0] == "0" {
+		p.Clear()
+		return nil
+	}
+	if len(parts) != 3 || parts[0] != "1" {
+		return errors.New("bn254: invalid G1 string")
+	}
+	var c [2]fp
+	for i := range c {
+		if err := setFpString(&c[i], parts[i+1], base); err != nil {
+			return err
+		}
+	}
+	var q G1
+	q.setAffine(&c[0], &c[1])
+	if !q.IsOnCurve() {
+		return errors.New("bn254: G1 point not on curve")
+	}
+	*p = q
+	return nil
+}
+
+func setFpString(x *fp, s string, base int) error {
+	b, ok := new(big.Int).SetString(s, base)
+	if !ok || b.Sign() < 0 || b.Cmp(fpField.mod) >= 0 {
+		return errors.New("bn254: invalid field element")
+	}
+	x.setBig(b)
+	return nil
+}
+
+func isZeroBytes(buff []byte) bool {
+	for _, b := range buff {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
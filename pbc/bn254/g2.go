@@ -0,0 +1,316 @@
+package bn254
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// G2Size is the size in bytes of a serialized G2 point.
+const G2Size = 64
+
+// twistB is the constant of the twist y^2 = x^3 + b/xi = x^3 + 1 - i.
+var twistB fp2
+
+func init() {
+	twistB.a.setInt64(1)
+	twistB.b.setInt64(-1)
+}
+
+// G2 is a point on the twist y^2 = x^3 + 1 - i over Fp2, in Jacobian
+// coordinates.
+// The zero value is the point at infinity.
+type G2 struct {
+	x, y, z fp2
+}
+
+// Clear sets p to the point at infinity.
+func (p *G2) Clear() {
+	*p = G2{}
+}
+
+// IsZero returns true if p is the point at infinity.
+func (p *G2) IsZero() bool {
+	return p.z.isZero()
+}
+
+// IsEqual returns true if both points are equal.
+func (p *G2) IsEqual(q *G2) bool {
+	if p.IsZero() || q.IsZero() {
+		return p.IsZero() && q.IsZero()
+	}
+	var z1, z2, t1, t2 fp2
+	z1.sqr(&p.z)
+	z2.sqr(&q.z)
+	t1.mul(&p.x, &z2)
+	t2.mul(&q.x, &z1)
+	if !t1.equal(&t2) {
+		return false
+	}
+	z1.mul(&z1, &p.z)
+	z2.mul(&z2, &q.z)
+	t1.mul(&p.y, &z2)
+	t2.mul(&q.y, &z1)
+	return t1.equal(&t2)
+}
+
+// IsOnCurve returns true if p satisfies the curve equation.
+func (p *G2) IsOnCurve() bool {
+	if p.IsZero() {
+		return true
+	}
+	var a G2
+	a.set(p)
+	a.normalize()
+	var l, r fp2
+	l.sqr(&a.y)
+	r.sqr(&a.x)
+	r.mul(&r, &a.x)
+	r.add(&r, &twistB)
+	return l.equal(&r)
+}
+
+func (p *G2) set(q *G2) {
+	*p = *q
+}
+
+// setAffine sets p to the affine point (x,y).
+func (p *G2) setAffine(x, y *fp2) {
+	p.x.set(x)
+	p.y.set(y)
+	p.z.setOne()
+}
+
+// normalize converts p to affine coordinates, i.e. z = 1.
+func (p *G2) normalize() {
+	if p.IsZero() || p.z.isOne() {
+		return
+	}
+	var zi, zi2 fp2
+	zi.inv(&p.z)
+	zi2.sqr(&zi)
+	p.x.mul(&p.x, &zi2)
+	zi2.mul(&zi2, &zi)
+	p.y.mul(&p.y, &zi2)
+	p.z.setOne()
+}
+
+// G2Neg sets z = -x.
+func G2Neg(z, x *G2) {
+	z.x.set(&x.x)
+	z.y.neg(&x.y)
+	z.z.set(&x.z)
+}
+
+// G2Dbl sets z = 2x.
+func G2Dbl(z, x *G2) {
+	if x.IsZero() {
+		z.Clear()
+		return
+	}
+	var a, b, c, d, e, f, t fp2
+	a.sqr(&x.x)
+	b.sqr(&x.y)
+	c.sqr(&b)
+	d.add(&x.x, &b)
+	d.sqr(&d)
+	d.sub(&d, &a)
+	d.sub(&d, &c)
+	d.add(&d, &d)
+	e.add(&a, &a)
+	e.add(&e, &a)
+	f.sqr(&e)
+
+	var x3, y3, z3 fp2
+	x3.sub(&f, &d)
+	x3.sub(&x3, &d)
+	t.add(&c, &c)
+	t.add(&t, &t)
+	t.add(&t, &t)
+	y3.sub(&d, &x3)
+	y3.mul(&y3, &e)
+	y3.sub(&y3, &t)
+	z3.mul(&x.y, &x.z)
+	z3.add(&z3, &z3)
+	z.x, z.y, z.z = x3, y3, z3
+}
+
+// G2Add sets z = x + y.
+func G2Add(z, x, y *G2) {
+	if x.IsZero() {
+		z.set(y)
+		return
+	}
+	if y.IsZero() {
+		z.set(x)
+		return
+	}
+	var z1z1, z2z2, u1, u2, s1, s2, h, i, j, r, v fp2
+	z1z1.sqr(&x.z)
+	z2z2.sqr(&y.z)
+	u1.mul(&x.x, &z2z2)
+	u2.mul(&y.x, &z1z1)
+	s1.mul(&x.y, &y.z)
+	s1.mul(&s1, &z2z2)
+	s2.mul(&y.y, &x.z)
+	s2.mul(&s2, &z1z1)
+	h.sub(&u2, &u1)
+	r.sub(&s2, &s1)
+	if h.isZero() {
+		if r.isZero() {
+			G2Dbl(z, x)
+		} else {
+			z.Clear()
+		}
+		return
+	}
+	r.add(&r, &r)
+	i.add(&h, &h)
+	i.sqr(&i)
+	j.mul(&h, &i)
+	v.mul(&u1, &i)
+
+	var x3, y3, z3, t fp2
+	x3.sqr(&r)
+	x3.sub(&x3, &j)
+	x3.sub(&x3, &v)
+	x3.sub(&x3, &v)
+	y3.sub(&v, &x3)
+	y3.mul(&y3, &r)
+	t.mul(&s1, &j)
+	t.add(&t, &t)
+	y3.sub(&y3, &t)
+	z3.add(&x.z, &y.z)
+	z3.sqr(&z3)
+	z3.sub(&z3, &z1z1)
+	z3.sub(&z3, &z2z2)
+	z3.mul(&z3, &h)
+	z.x, z.y, z.z = x3, y3, z3
+}
+
+// G2Sub sets z = x - y.
+func G2Sub(z, x, y *G2) {
+	var t G2
+	G2Neg(&t, y)
+	G2Add(z, x, &t)
+}
+
+// G2Mul sets z = s * x.
+func G2Mul(z, x *G2, s *Fr) {
+	g2MulBig(z, x, s.Big())
+}
+
+// g2MulBig sets z = k * x for a non-negative integer k.
+func g2MulBig(z, x *G2, k *big.Int) {
+	var r G2
+	base := *x
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		G2Dbl(&r, &r)
+		if k.Bit(i) == 1 {
+			G2Add(&r, &r, &base)
+		}
+	}
+	*z = r
+}
+
+// Serialize returns the mcl encoding of p: both little-endian coordinates of
+// x with the highest bit set if y is odd. The point at infinity is encoded as
+// zeros.
+func (p *G2) Serialize() []byte {
+	buff := make([]byte, G2Size)
+	if p.IsZero() {
+		return buff
+	}
+	var a G2
+	a.set(p)
+	a.normalize()
+	a.x.a.bytes(buff[:G2Size/2])
+	a.x.b.bytes(buff[G2Size/2:])
+	if a.y.isOdd() {
+		buff[G2Size-1] |= 0x80
+	}
+	return buff
+}
+
+// Deserialize reads an encoding produced by Serialize. It returns an error if
+// the encoding does not represent a point on the curve.
+func (p *G2) Deserialize(buff []byte) error {
+	if len(buff) != G2Size {
+		return errors.New("bn254: wrong size for G2")
+	}
+	if isZeroBytes(buff) {
+		p.Clear()
+		return nil
+	}
+	b := make([]byte, G2Size)
+	copy(b, buff)
+	odd := b[G2Size-1]&0x80 != 0
+	b[G2Size-1] &= 0x7f
+	var x, y fp2
+	if err := x.a.setBytes(b[:G2Size/2]); err != nil {
+		return err
+	}
+	if err := x.b.setBytes(b[G2Size/2:]); err != nil {
+		return err
+	}
+	if !g2YFromX(&y, &x, odd) {
+		return errors.New("bn254: G2 point not on curve")
+	}
+	p.setAffine(&x, &y)
+	return nil
+}
+
+// g2YFromX computes y such that (x,y) is on the curve with the given parity.
+func g2YFromX(y, x *fp2, odd bool) bool {
+	var t fp2
+	t.sqr(x)
+	t.mul(&t, x)
+	t.add(&t, &twistB)
+	if !y.sqrt(&t) {
+		return false
+	}
+	if y.isOdd() != odd {
+		y.neg(y)
+	}
+	return true
+}
+
+// GetString returns "0" for the point at infinity and "1 x.a x.b y.a y.b"
+// with the affine coordinates otherwise, like mcl does.
+func (p *G2) GetString(base int) string {
+	if p.IsZero() {
+		return "0"
+	}
+	var a G2
+	a.set(p)
+	a.normalize()
+	return fmt.Sprintf("1 %s %s %s %s", a.x.a.big().Text(base), a.x.b.big().Text(base),
+		a.y.a.big().Text(base), a.y.b.big().Text(base))
+}
+
+// SetString reads a point from the format returned by GetString. It returns
+// an error if the point is not on the curve.
+func (p *G2) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) == 1 && parts[0] == "0" {
+		p.Clear()
+		return nil
+	}
+	if len(parts) != 5 || parts[0] != "1" {
+		return errors.New("bn254: invalid G2 string")
+	}
+	var c [4]fp
+	for i := range c {
+		if err := setFpString(&c[i], parts[i+1], base); err != nil {
+			return err
+		}
+	}
+	var q G2
+	q.setAffine(&fp2{c[0], c[1]}, &fp2{c[2], c[3]})
+	if !q.IsOnCurve() {
+		return errors.New("bn254: G2 point not on curve")
+	}
+	*p = q
+	return nil
+}
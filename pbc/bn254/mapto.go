@@ -0,0 +1,191 @@
+package bn254
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+// constants of the Fouque-Tibouchi map: c1 = sqrt(-3), c2 = (-1 + c1) / 2
+var mapC1, mapC2 fp
+
+func init() {
+	var t fp
+	t.setInt64(-3)
+	if !mapC1.sqrt(&t) {
+		panic("bn254: -3 is not a square")
+	}
+	t.setInt64(-1)
+	mapC2.add(&t, &mapC1)
+	mapC2.halve(&mapC2)
+}
+
+// hashToFp computes the mcl hash of msg: the little-endian SHA-256 digest
+// with every bit at or above the bit length of p minus one cleared.
+func hashToFp(msg []byte) fp {
+	h := sha256.Sum256(msg)
+	w := maskedWords(h[:], fpField.bits-1)
+	var t fp
+	fpField.toMont(fw(&t), &w)
+	return t
+}
+
+var errMapTo = errors.New("bn254: cannot map to point")
+
+// HashAndMapTo sets p to the point of G1 that msg maps to, the same way mcl
+// does: SHA-256 followed by the Fouque-Tibouchi encoding.
+func (p *G1) HashAndMapTo(msg []byte) error {
+	t := hashToFp(msg)
+	var x, y fp
+	if !calcBN1(&x, &y, &t) {
+		return errMapTo
+	}
+	p.setAffine(&x, &y)
+	return nil
+}
+
+// HashAndMapTo sets p to the point of G2 that msg maps to, the same way mcl
+// does: the hash is embedded in Fp2, encoded with Fouque-Tibouchi and
+// multiplied by the cofactor.
+func (p *G2) HashAndMapTo(msg []byte) error {
+	var t fp2
+	t.a = hashToFp(msg)
+	var x, y fp2
+	if !calcBN2(&x, &y, &t) {
+		return errMapTo
+	}
+	var q G2
+	q.setAffine(&x, &y)
+	g2MulCofactor(p, &q)
+	return nil
+}
+
+// calcBN1 is the encoding of "Indifferentiable Hashing to Barreto-Naehrig
+// Curves" over Fp.
+func calcBN1(x, y, t *fp) bool {
+	if t.isZero() {
+		return false
+	}
+	negative := t.legendre() < 0
+	var w, one fp
+	one.setOne()
+	w.sqr(t)
+	w.add(&w, &curveB)
+	w.add(&w, &one)
+	if w.isZero() {
+		return false
+	}
+	w.inv(&w)
+	w.mul(&w, &mapC1)
+	w.mul(&w, t)
+	for i := 0; i < 3; i++ {
+		switch i {
+		case 0:
+			x.mul(t, &w)
+			x.neg(x)
+			x.add(x, &mapC2)
+		case 1:
+			x.neg(x)
+			x.sub(x, &one)
+		case 2:
+			x.sqr(&w)
+			x.inv(x)
+			x.add(x, &one)
+		}
+		var r fp
+		r.sqr(x)
+		r.mul(&r, x)
+		r.add(&r, &curveB)
+		if y.sqrt(&r) {
+			if negative {
+				y.neg(y)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// calcBN2 is the same encoding as calcBN1 over Fp2, on the twist.
+func calcBN2(x, y, t *fp2) bool {
+	if t.isZero() {
+		return false
+	}
+	negative := t.legendre() < 0
+	var w, one fp2
+	one.setOne()
+	w.sqr(t)
+	w.add(&w, &twistB)
+	w.add(&w, &one)
+	if w.isZero() {
+		return false
+	}
+	w.inv(&w)
+	w.mulFp(&w, &mapC1)
+	w.mul(&w, t)
+	var c2 fp2
+	c2.a.set(&mapC2)
+	for i := 0; i < 3; i++ {
+		switch i {
+		case 0:
+			x.mul(t, &w)
+			x.neg(x)
+			x.add(x, &c2)
+		case 1:
+			x.neg(x)
+			x.sub(x, &one)
+		case 2:
+			x.sqr(&w)
+			x.inv(x)
+			x.add(x, &one)
+		}
+		var r fp2
+		r.sqr(x)
+		r.mul(&r, x)
+		r.add(&r, &twistB)
+		if y.sqrt(&r) {
+			if negative {
+				y.neg(y)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// g2Frobenius sets z = psi(x), the endomorphism of the twist induced by the
+// p-power Frobenius.
+func g2Frobenius(z, x *G2) {
+	z.x.conj(&x.x)
+	z.x.mul(&z.x, &frobGamma[2])
+	z.y.conj(&x.y)
+	z.y.mul(&z.y, &frobGamma[3])
+	z.z.conj(&x.z)
+}
+
+// g2MulCofactor clears the cofactor of x with the method of "Fast Hashing to
+// G2 on Pairing-Friendly Curves":
+//
+//	zP + psi(3zP) + psi^2(zP) + psi^3(P)
+func g2MulCofactor(z, x *G2) {
+	var zp, t0, t1, r G2
+	g2MulBig(&zp, x, absU)
+	G2Neg(&zp, &zp) // u < 0
+	G2Add(&t0, &zp, &zp)
+	G2Add(&t0, &t0, &zp)
+	g2Frobenius(&t0, &t0)
+	G2Add(&r, &zp, &t0)
+	g2Frobenius(&t1, &zp)
+	g2Frobenius(&t1, &t1)
+	G2Add(&r, &r, &t1)
+	g2Frobenius(&t1, x)
+	g2Frobenius(&t1, &t1)
+	g2Frobenius(&t1, &t1)
+	G2Add(z, &r, &t1)
+}
+
+// g2InSubgroup returns true if r * x is the point at infinity.
+func g2InSubgroup(x *G2) bool {
+	var t G2
+	g2MulBig(&t, x, frField.mod)
+	return t.IsZero()
+}
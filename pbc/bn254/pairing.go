@@ -0,0 +1,287 @@
+package bn254
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// GTSize is the size in bytes of a serialized GT element.
+const GTSize = 12 * 32
+
+// GT is an element of the target group, a subgroup of Fp12*. The zero value is
+// NOT a valid element, use SetInt64(1) to get the identity.
+type GT struct {
+	f fp12
+}
+
+// u is the parameter of the curve: p = 36u^4 + 36u^3 + 24u^2 + 6u + 1.
+var u = big.NewInt(-((1 << 62) + (1 << 55) + 1))
+
+// ateLoop is |6u + 2|, the length of the Miller loop of the optimal ate pairing.
+var ateLoop = new(big.Int).Abs(new(big.Int).Add(new(big.Int).Mul(big.NewInt(6), u), big.NewInt(2)))
+
+// absU is |u|
+var absU = new(big.Int).Abs(u)
+
+// Clear sets x to zero, which is not an element of GT.
+func (x *GT) Clear() {
+	x.f = fp12{}
+}
+
+// SetInt64 sets x to the constant v.
+func (x *GT) SetInt64(v int64) {
+	x.f = fp12{}
+	x.f.a.a.a.setInt64(v)
+}
+
+// IsEqual returns true if both elements are equal.
+func (x *GT) IsEqual(y *GT) bool {
+	return x.f.equal(&y.f)
+}
+
+// IsOne returns true if x is the identity of GT.
+func (x *GT) IsOne() bool {
+	return x.f.isOne()
+}
+
+// coeffs returns the twelve coordinates of x in mcl's order.
+func (x *GT) coeffs() [12]*fp {
+	f := &x.f
+	return [12]*fp{
+		&f.a.a.a, &f.a.a.b, &f.a.b.a, &f.a.b.b, &f.a.c.a, &f.a.c.b,
+		&f.b.a.a, &f.b.a.b, &f.b.b.a, &f.b.b.b, &f.b.c.a, &f.b.c.b,
+	}
+}
+
+// Serialize returns the concatenation of the twelve little-endian coordinates
+// of x.
+func (x *GT) Serialize() []byte {
+	buff := make([]byte, GTSize)
+	for i, c := range x.coeffs() {
+		c.bytes(buff[i*32 : (i+1)*32])
+	}
+	return buff
+}
+
+// Deserialize reads an encoding produced by Serialize. It only checks that
+// every coordinate is reduced.
+func (x *GT) Deserialize(buff []byte) error {
+	if len(buff) != GTSize {
+		return errors.New("bn254: wrong size for GT")
+	}
+	var y GT
+	for i, c := range y.coeffs() {
+		if err := c.setBytes(buff[i*32 : (i+1)*32]); err != nil {
+			return err
+		}
+	}
+	*x = y
+	return nil
+}
+
+// GetString returns the twelve coordinates separated by spaces.
+func (x *GT) GetString(base int) string {
+	var s []string
+	for _, c := range x.coeffs() {
+		s = append(s, c.big().Text(base))
+	}
+	return strings.Join(s, " ")
+}
+
+// SetString reads the format returned by GetString.
+func (x *GT) SetString(s string, base int) error {
+	parts := strings.Fields(s)
+	if len(parts) != 12 {
+		return fmt.Errorf("bn254: invalid GT string")
+	}
+	var y GT
+	for i, c := range y.coeffs() {
+		if err := setFpString(c, parts[i], base); err != nil {
+			return err
+		}
+	}
+	*x = y
+	return nil
+}
+
+// GTMul sets z = x * y.
+func GTMul(z, x, y *GT) {
+	z.f.mul(&x.f, &y.f)
+}
+
+// GTDiv sets z = x / y.
+func GTDiv(z, x, y *GT) {
+	var t fp12
+	t.inv(&y.f)
+	z.f.mul(&x.f, &t)
+}
+
+// GTInv sets z = 1 / x.
+func GTInv(z, x *GT) {
+	z.f.inv(&x.f)
+}
+
+// GTPow sets z = x^s.
+func GTPow(z, x *GT, s *Fr) {
+	z.f.exp(&x.f, s.Big())
+}
+
+// Pairing sets z = e(x, y), the optimal ate pairing of x and y.
+func Pairing(z *GT, x *G1, y *G2) {
+	MillerLoop(z, x, y)
+	FinalExp(z, z)
+}
+
+// MillerLoop sets z to the Miller loop of the optimal ate pairing of x and y,
+// i.e. the pairing before the final exponentiation.
+func MillerLoop(z *GT, x *G1, y *G2) {
+	if x.IsZero() || y.IsZero() {
+		z.SetInt64(1)
+		return
+	}
+	var p G1
+	p.set(x)
+	p.normalize()
+	var q G2
+	q.set(y)
+	q.normalize()
+
+	var f fp12
+	f.setOne()
+	t := affine2{x: q.x, y: q.y}
+	qa := affine2{x: q.x, y: q.y}
+	for i := ateLoop.BitLen() - 2; i >= 0; i-- {
+		f.sqr(&f)
+		t.line(&f, &t, &p)
+		if ateLoop.Bit(i) == 1 {
+			t.line(&f, &qa, &p)
+		}
+	}
+	// u < 0
+	f.conj(&f)
+	t.y.neg(&t.y)
+
+	// Q1 = psi(Q), Q2 = -psi^2(Q)
+	var q1, q2 affine2
+	q1.frobenius(&qa)
+	q2.frobenius(&q1)
+	q2.y.neg(&q2.y)
+	t.line(&f, &q1, &p)
+	t.line(&f, &q2, &p)
+	z.f = f
+}
+
+// FinalExp sets z = x^((p^12 - 1) / r * m) where m = 2u(6u^2 + 3u + 1). The
+// extra factor m comes from the hard part of Fuentes-Castaneda et al., used by
+// mcl, and is kept so both libraries give the same results.
+func FinalExp(z, x *GT) {
+	var f, t fp12
+	// easy part: f^((p^6 - 1)(p^2 + 1))
+	t.inv(&x.f)
+	f.conj(&x.f)
+	f.mul(&f, &t)
+	t.frobenius(&f)
+	t.frobenius(&t)
+	f.mul(&f, &t)
+
+	// hard part, see "Faster Hashing to G2" section 4.1
+	var xz, x2z, x4z, x6z, x6z2, x12z2, x12z3, a, b, c fp12
+	powU(&xz, &f)
+	x2z.sqr(&xz)
+	x4z.sqr(&x2z)
+	x6z.mul(&x4z, &x2z)
+	powU(&x6z2, &x6z)
+	x12z2.sqr(&x6z2)
+	powU(&x12z3, &x12z2)
+	a.mul(&x6z, &x6z2)
+	a.mul(&a, &x12z3)
+	t.conj(&x2z)
+	b.mul(&a, &t)
+
+	// (a x^(6z^2) x) b^p a^(p^2) (b / x)^(p^3)
+	c.mul(&a, &x6z2)
+	c.mul(&c, &f)
+	t.frobenius(&b)
+	c.mul(&c, &t)
+	t.frobenius(&a)
+	t.frobenius(&t)
+	c.mul(&c, &t)
+	t.conj(&f)
+	t.mul(&t, &b)
+	t.frobenius(&t)
+	t.frobenius(&t)
+	t.frobenius(&t)
+	z.f.mul(&c, &t)
+}
+
+// powU sets z = x^u for x in the cyclotomic subgroup, where the inverse is the
+// conjugate.
+func powU(z, x *fp12) {
+	z.exp(x, absU)
+	z.conj(z)
+}
+
+// affine2 is an affine point on the twist used during the Miller loop.
+type affine2 struct {
+	x, y fp2
+}
+
+// frobenius sets a = psi(b), the p-power Frobenius endomorphism on the twist.
+func (a *affine2) frobenius(b *affine2) {
+	a.x.conj(&b.x)
+	a.x.mul(&a.x, &frobGamma[2])
+	a.y.conj(&b.y)
+	a.y.mul(&a.y, &frobGamma[3])
+}
+
+// line multiplies f by the line going through a and b evaluated at p, and sets
+// a = a + b. Vertical lines are ignored since they vanish in the final
+// exponentiation.
+//
+// The untwisting map sends (x, y) to (x w^2, y w^3) so the line through
+// a with slope l evaluated at p = (xp, yp) is
+//
+//	yp - l xp w + (l xa - ya) w^3
+func (a *affine2) line(f *fp12, b *affine2, p *G1) {
+	var l, t fp2
+	if a.x.equal(&b.x) {
+		if !a.y.equal(&b.y) || a.y.isZero() {
+			// vertical line, a + b = 0
+			a.x.setZero()
+			a.y.setZero()
+			return
+		}
+		// tangent: l = 3x^2 / 2y
+		l.sqr(&a.x)
+		t.add(&l, &l)
+		l.add(&l, &t)
+		t.add(&a.y, &a.y)
+		t.inv(&t)
+		l.mul(&l, &t)
+	} else {
+		l.sub(&b.y, &a.y)
+		t.sub(&b.x, &a.x)
+		t.inv(&t)
+		l.mul(&l, &t)
+	}
+
+	var ln fp12
+	ln.a.a.a.set(&p.y)
+	ln.b.a.mulFp(&l, &p.x)
+	ln.b.a.neg(&ln.b.a)
+	ln.b.b.mul(&l, &a.x)
+	ln.b.b.sub(&ln.b.b, &a.y)
+	f.mul(f, &ln)
+
+	// x3 = l^2 - xa - xb, y3 = l(xa - x3) - ya
+	var x3, y3 fp2
+	x3.sqr(&l)
+	x3.sub(&x3, &a.x)
+	x3.sub(&x3, &b.x)
+	y3.sub(&a.x, &x3)
+	y3.mul(&y3, &l)
+	y3.sub(&y3, &a.y)
+	a.x, a.y = x3, y3
+}
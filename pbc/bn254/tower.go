@@ -0,0 +1,433 @@
+package bn254
+
+import "math/big"
+
+// The extension tower follows the one of the mcl library:
+//
+//   Fp2  = Fp[i]  / (i^2 + 1)
+//   Fp6  = Fp2[v] / (v^3 - xi)   with xi = 1 + i
+//   Fp12 = Fp6[w] / (w^2 - v)
+
+// fp2 is the element a + b*i.
+type fp2 struct {
+	a, b fp
+}
+
+func (z *fp2) set(x *fp2) *fp2 {
+	*z = *x
+	return z
+}
+
+func (z *fp2) setZero() *fp2 {
+	*z = fp2{}
+	return z
+}
+
+func (z *fp2) setOne() *fp2 {
+	z.a.setOne()
+	z.b.setZero()
+	return z
+}
+
+func (z *fp2) isZero() bool {
+	return z.a.isZero() && z.b.isZero()
+}
+
+func (z *fp2) isOne() bool {
+	return z.a.isOne() && z.b.isZero()
+}
+
+func (z *fp2) equal(x *fp2) bool {
+	return z.a.equal(&x.a) && z.b.equal(&x.b)
+}
+
+func (z *fp2) add(x, y *fp2) *fp2 {
+	z.a.add(&x.a, &y.a)
+	z.b.add(&x.b, &y.b)
+	return z
+}
+
+func (z *fp2) sub(x, y *fp2) *fp2 {
+	z.a.sub(&x.a, &y.a)
+	z.b.sub(&x.b, &y.b)
+	return z
+}
+
+func (z *fp2) neg(x *fp2) *fp2 {
+	z.a.neg(&x.a)
+	z.b.neg(&x.b)
+	return z
+}
+
+// conj sets z to the conjugate of x, which is also x^p.
+func (z *fp2) conj(x *fp2) *fp2 {
+	z.a.set(&x.a)
+	z.b.neg(&x.b)
+	return z
+}
+
+func (z *fp2) mul(x, y *fp2) *fp2 {
+	var t0, t1, t2, t3 fp
+	t0.mul(&x.a, &y.a)
+	t1.mul(&x.b, &y.b)
+	t2.add(&x.a, &x.b)
+	t3.add(&y.a, &y.b)
+	t2.mul(&t2, &t3)
+	t2.sub(&t2, &t0)
+	z.b.sub(&t2, &t1)
+	z.a.sub(&t0, &t1)
+	return z
+}
+
+func (z *fp2) sqr(x *fp2) *fp2 {
+	// (a + bi)^2 = (a + b)(a - b) + 2abi
+	var t0, t1, t2 fp
+	t0.add(&x.a, &x.b)
+	t1.sub(&x.a, &x.b)
+	t2.mul(&x.a, &x.b)
+	z.a.mul(&t0, &t1)
+	z.b.add(&t2, &t2)
+	return z
+}
+
+// mulFp sets z = x * y with y in Fp.
+func (z *fp2) mulFp(x *fp2, y *fp) *fp2 {
+	z.a.mul(&x.a, y)
+	z.b.mul(&x.b, y)
+	return z
+}
+
+// mulXi sets z = x * (1 + i).
+func (z *fp2) mulXi(x *fp2) *fp2 {
+	var t fp
+	t.sub(&x.a, &x.b)
+	z.b.add(&x.a, &x.b)
+	z.a.set(&t)
+	return z
+}
+
+// norm returns a^2 + b^2.
+func (z *fp2) norm(n *fp) {
+	var t fp
+	n.sqr(&z.a)
+	t.sqr(&z.b)
+	n.add(n, &t)
+}
+
+func (z *fp2) inv(x *fp2) *fp2 {
+	var n fp
+	x.norm(&n)
+	n.inv(&n)
+	z.a.mul(&x.a, &n)
+	n.neg(&n)
+	z.b.mul(&x.b, &n)
+	return z
+}
+
+// exp sets z = x^e for a public exponent e.
+func (z *fp2) exp(x *fp2, e *big.Int) *fp2 {
+	var res fp2
+	res.setOne()
+	base := *x
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.sqr(&res)
+		if e.Bit(i) == 1 {
+			res.mul(&res, &base)
+		}
+	}
+	*z = res
+	return z
+}
+
+// legendre is computed through the norm of the element.
+func (z *fp2) legendre() int {
+	var n fp
+	z.norm(&n)
+	return n.legendre()
+}
+
+// isOdd follows mcl's convention which looks at the first coordinate only.
+func (z *fp2) isOdd() bool {
+	return z.a.isOdd()
+}
+
+// sqrt sets z to a square root of x and returns true if x is a square. It
+// follows the same algorithm as mcl so both libraries return the same root.
+func (z *fp2) sqrt(x *fp2) bool {
+	var t1, t2 fp
+	if x.b.isZero() {
+		if t1.sqrt(&x.a) {
+			z.a.set(&t1)
+			z.b.setZero()
+		} else {
+			t2.neg(&x.a)
+			if !t1.sqrt(&t2) {
+				return false
+			}
+			z.a.setZero()
+			z.b.set(&t1)
+		}
+		return true
+	}
+	t1.sqr(&x.a)
+	t2.sqr(&x.b)
+	t1.add(&t1, &t2) // |x|^2
+	if !t1.sqrt(&t1) {
+		return false
+	}
+	t2.add(&x.a, &t1)
+	t2.halve(&t2)
+	if !t2.sqrt(&t2) {
+		t2.sub(&x.a, &t1)
+		t2.halve(&t2)
+		if !t2.sqrt(&t2) {
+			return false
+		}
+	}
+	var b fp
+	b.add(&t2, &t2)
+	b.inv(&b)
+	b.mul(&x.b, &b)
+	z.a.set(&t2)
+	z.b.set(&b)
+	return true
+}
+
+// fp6 is the element a + b*v + c*v^2.
+type fp6 struct {
+	a, b, c fp2
+}
+
+func (z *fp6) setZero() *fp6 {
+	*z = fp6{}
+	return z
+}
+
+func (z *fp6) setOne() *fp6 {
+	z.a.setOne()
+	z.b.setZero()
+	z.c.setZero()
+	return z
+}
+
+func (z *fp6) isZero() bool {
+	return z.a.isZero() && z.b.isZero() && z.c.isZero()
+}
+
+func (z *fp6) isOne() bool {
+	return z.a.isOne() && z.b.isZero() && z.c.isZero()
+}
+
+func (z *fp6) equal(x *fp6) bool {
+	return z.a.equal(&x.a) && z.b.equal(&x.b) && z.c.equal(&x.c)
+}
+
+func (z *fp6) add(x, y *fp6) *fp6 {
+	z.a.add(&x.a, &y.a)
+	z.b.add(&x.b, &y.b)
+	z.c.add(&x.c, &y.c)
+	return z
+}
+
+func (z *fp6) sub(x, y *fp6) *fp6 {
+	z.a.sub(&x.a, &y.a)
+	z.b.sub(&x.b, &y.b)
+	z.c.sub(&x.c, &y.c)
+	return z
+}
+
+func (z *fp6) neg(x *fp6) *fp6 {
+	z.a.neg(&x.a)
+	z.b.neg(&x.b)
+	z.c.neg(&x.c)
+	return z
+}
+
+func (z *fp6) mul(x, y *fp6) *fp6 {
+	var t0, t1, t2, s, u fp2
+	t0.mul(&x.a, &y.a)
+	t1.mul(&x.b, &y.b)
+	t2.mul(&x.c, &y.c)
+
+	// a = a0b0 + xi(a1b2 + a2b1)
+	var a, b, c fp2
+	s.mul(&x.b, &y.c)
+	u.mul(&x.c, &y.b)
+	s.add(&s, &u)
+	s.mulXi(&s)
+	a.add(&t0, &s)
+
+	// b = a0b1 + a1b0 + xi a2b2
+	s.mul(&x.a, &y.b)
+	u.mul(&x.b, &y.a)
+	s.add(&s, &u)
+	u.mulXi(&t2)
+	b.add(&s, &u)
+
+	// c = a0b2 + a1b1 + a2b0
+	s.mul(&x.a, &y.c)
+	u.mul(&x.c, &y.a)
+	s.add(&s, &u)
+	c.add(&s, &t1)
+
+	z.a, z.b, z.c = a, b, c
+	return z
+}
+
+func (z *fp6) sqr(x *fp6) *fp6 {
+	return z.mul(x, x)
+}
+
+// mulV sets z = x * v.
+func (z *fp6) mulV(x *fp6) *fp6 {
+	var t fp2
+	t.mulXi(&x.c)
+	z.c.set(&x.b)
+	z.b.set(&x.a)
+	z.a.set(&t)
+	return z
+}
+
+func (z *fp6) inv(x *fp6) *fp6 {
+	var t0, t1, t2, s, d fp2
+	// t0 = a^2 - xi bc
+	t0.sqr(&x.a)
+	s.mul(&x.b, &x.c)
+	s.mulXi(&s)
+	t0.sub(&t0, &s)
+	// t1 = xi c^2 - ab
+	t1.sqr(&x.c)
+	t1.mulXi(&t1)
+	s.mul(&x.a, &x.b)
+	t1.sub(&t1, &s)
+	// t2 = b^2 - ac
+	t2.sqr(&x.b)
+	s.mul(&x.a, &x.c)
+	t2.sub(&t2, &s)
+	// d = a t0 + xi (c t1 + b t2)
+	d.mul(&x.c, &t1)
+	s.mul(&x.b, &t2)
+	d.add(&d, &s)
+	d.mulXi(&d)
+	s.mul(&x.a, &t0)
+	d.add(&d, &s)
+	d.inv(&d)
+
+	z.a.mul(&t0, &d)
+	z.b.mul(&t1, &d)
+	z.c.mul(&t2, &d)
+	return z
+}
+
+// fp12 is the element a + b*w.
+type fp12 struct {
+	a, b fp6
+}
+
+func (z *fp12) setOne() *fp12 {
+	z.a.setOne()
+	z.b.setZero()
+	return z
+}
+
+func (z *fp12) isOne() bool {
+	return z.a.isOne() && z.b.isZero()
+}
+
+func (z *fp12) isZero() bool {
+	return z.a.isZero() && z.b.isZero()
+}
+
+func (z *fp12) equal(x *fp12) bool {
+	return z.a.equal(&x.a) && z.b.equal(&x.b)
+}
+
+func (z *fp12) mul(x, y *fp12) *fp12 {
+	var t0, t1, s, u fp6
+	t0.mul(&x.a, &y.a)
+	t1.mul(&x.b, &y.b)
+	s.mul(&x.a, &y.b)
+	u.mul(&x.b, &y.a)
+	z.b.add(&s, &u)
+	t1.mulV(&t1)
+	z.a.add(&t0, &t1)
+	return z
+}
+
+func (z *fp12) sqr(x *fp12) *fp12 {
+	return z.mul(x, x)
+}
+
+// conj sets z = x^(p^6), which is the inverse of x in the cyclotomic subgroup.
+func (z *fp12) conj(x *fp12) *fp12 {
+	z.a = x.a
+	z.b.neg(&x.b)
+	return z
+}
+
+func (z *fp12) inv(x *fp12) *fp12 {
+	var t0, t1 fp6
+	t0.sqr(&x.a)
+	t1.sqr(&x.b)
+	t1.mulV(&t1)
+	t0.sub(&t0, &t1)
+	t0.inv(&t0)
+	z.a.mul(&x.a, &t0)
+	t0.neg(&t0)
+	z.b.mul(&x.b, &t0)
+	return z
+}
+
+// exp sets z = x^e for a public exponent. Negative exponents use the inverse.
+func (z *fp12) exp(x *fp12, e *big.Int) *fp12 {
+	base := *x
+	if e.Sign() < 0 {
+		base.inv(&base)
+		e = new(big.Int).Neg(e)
+	}
+	var res fp12
+	res.setOne()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.sqr(&res)
+		if e.Bit(i) == 1 {
+			res.mul(&res, &base)
+		}
+	}
+	*z = res
+	return z
+}
+
+// frobenius sets z = x^p. Writing x = sum c_j w^j with c_j in Fp2, x^p is
+// sum conj(c_j) * xi^(j(p-1)/6) w^j.
+func (z *fp12) frobenius(x *fp12) *fp12 {
+	z.a.a.conj(&x.a.a)
+	z.a.b.conj(&x.a.b)
+	z.a.c.conj(&x.a.c)
+	z.b.a.conj(&x.b.a)
+	z.b.b.conj(&x.b.b)
+	z.b.c.conj(&x.b.c)
+	// a.a: w^0, b.a: w^1, a.b: w^2, b.b: w^3, a.c: w^4, b.c: w^5
+	z.b.a.mul(&z.b.a, &frobGamma[1])
+	z.a.b.mul(&z.a.b, &frobGamma[2])
+	z.b.b.mul(&z.b.b, &frobGamma[3])
+	z.a.c.mul(&z.a.c, &frobGamma[4])
+	z.b.c.mul(&z.b.c, &frobGamma[5])
+	return z
+}
+
+// frobGamma[j] = xi^(j(p-1)/6)
+var frobGamma [6]fp2
+
+func init() {
+	var xi fp2
+	xi.a.setOne()
+	xi.b.setOne()
+	e := new(big.Int).Sub(fpField.mod, big.NewInt(1))
+	e.Div(e, big.NewInt(6))
+	var g fp2
+	g.exp(&xi, e)
+	frobGamma[0].setOne()
+	for j := 1; j < 6; j++ {
+		frobGamma[j].mul(&frobGamma[j-1], &g)
+	}
+}
@@ -1,15 +1,17 @@
 package pbc
 
-import "github.com/dfinity/go-dfinity-crypto/bls"
-
 // CurveFp254BNb -- 254 bit curve
-const CurveFp254BNb = bls.CurveFp254BNb
+const CurveFp254BNb = 0
 
 // CurveFp382_1 -- 382 bit curve 1
-const CurveFp382_1 = bls.CurveFp382_1
+const CurveFp382_1 = 1
 
 // CurveFp382_2 -- 382 bit curve 2
-const CurveFp382_2 = bls.CurveFp382_2
+const CurveFp382_2 = 2
+
+// CurveBLS12_381 -- 381 bit BLS12 curve, with the identifier mcl gives it.
+// Only the pure Go backend implements it: build with -tags purego to use it.
+const CurveBLS12_381 = 5
 
 const Fp254_G1_Base_Seed = "Fp254_G1_Base_Seed"
 const Fp254_G2_Base_Seed = "Fp254_G2_Base_Seed"
@@ -34,3 +36,9 @@ const Fp382_1_G2_Base_Str = "1 13b386de009456a6fa70d8f84c7bdc4cf4a054364df9eab7f
 
 const Fp382_2_G1_Base_Str = "1 1a5613540dd853ba9b05ac5b3020829039293fefb51b0561f747d9dca8684dae62654d459ccf497ae38c1ecddb6b0a7 fa0db59db3c9e65eab755ee040ace01c5590925cbc4771c b58fc4e2d9bff378c58fc9a5c68f3a37781db4849c381904"
 const Fp382_2_G2_Base_Str = "1 1a664e3455aa61cf68c941197781451a1df89fc46441b8952b8e88f28058315ee6d4063c5f63949d41a8dad0b1ac64c4 3649627a44bf6aefa207d0121eae74e8c04f5c79c71dcd 5a72fa75314f6365998c7777f574395184930c327502e4d94 f149806d94796eb40d9fd86b14d5dadb1c0a8e55b87661de30e0dc6da238b13fa19de6d1f1d76219fe9e6c4f6fcbc48 1b93c82e0e03d01dad57c0 ef295e761f22ddeb970b115b7c6e1f8712cb3e5142a46d10df83b1d030c8737ce987725ad"
+
+// The generators of BLS12-381 are the standard ones rather than points
+// hashed from a seed, so signatures and keys interoperate with other
+// implementations.
+const BLS12_381_G1_Base_Str = "1 17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb 8b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1"
+const BLS12_381_G2_Base_Str = "1 24aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8 13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801 606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be"
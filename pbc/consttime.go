@@ -0,0 +1,193 @@
+package pbc
+
+import (
+	"crypto/subtle"
+	"math/big"
+	"unsafe"
+)
+
+// VarTimer is implemented by the points and scalars of this package.
+// SetVarTime(false) switches them to a uniform sequence of operations for the
+// values that depend on secrets:
+//   - Mul of a point runs a Montgomery ladder over a fixed number of bits with
+//     constant time swaps instead of using windows and precomputed tables,
+//     when either the point or the scalar is in this mode. MultiMul runs the
+//     ladder for each term when one of its operands is.
+//   - Inv and Div of a scalar compute the inverse by exponentiation to the
+//     public power r - 2, when one of the scalars is in this mode.
+//
+// The sequence of group and field operations then does not depend on the
+// secret, but this is not constant time at the field level: the additions of
+// the backends still branch on the identity and on doubling, and their field
+// arithmetic is only as constant time as it is. The other operations are
+// variable time since most values handled by the protocols are public.
+type VarTimer interface {
+	SetVarTime(varTime bool) error
+}
+
+func (p *pointG1) SetVarTime(varTime bool) error {
+	p.constTime = !varTime
+	return nil
+}
+
+func (p *pointG2) SetVarTime(varTime bool) error {
+	p.constTime = !varTime
+	return nil
+}
+
+func (p *pointGT) SetVarTime(varTime bool) error {
+	p.constTime = !varTime
+	return nil
+}
+
+func (s *scalar) SetVarTime(varTime bool) error {
+	s.constTime = !varTime
+	return nil
+}
+
+// ladderScalar returns the little-endian encoding of k + r or k + 2r,
+// whichever has its bit of index L = bitlen(r) set, selected in constant
+// time. Both are congruent to k, and for k < r one of them is in
+// [2^L, 2^(L+1)): if k + r < 2^L then 2^L <= 2r <= k + 2r < 2^L + r. The
+// ladder over the bits of the result thus always starts at bit L, so it runs
+// the same operations whatever the value of k.
+func ladderScalar(k []byte, r *big.Int) []byte {
+	n := len(k) + 1
+	r1 := make([]byte, n)
+	r2 := make([]byte, n)
+	putLittleEndian(r1, r)
+	putLittleEndian(r2, new(big.Int).Lsh(r, 1))
+	k1 := ctAddBytes(k, r1)
+	k2 := ctAddBytes(k, r2)
+	L := uint(r.BitLen())
+	bit := int(k1[L/8]>>(L%8)) & 1
+	subtle.ConstantTimeCopy(1-bit, k1, k2)
+	Zeroize(k2)
+	return k1
+}
+
+// ladderScalarOf returns the ladderScalar of s, wiping the intermediate
+// encoding. The caller wipes the result once the ladder is done.
+func ladderScalarOf(s *fr, r *big.Int) []byte {
+	buff := s.Serialize()
+	k := ladderScalar(buff, r)
+	Zeroize(buff)
+	return k
+}
+
+// ctAddBytes returns the sum of the little-endian numbers a and b, with the
+// length of b which must be longer than a.
+func ctAddBytes(a, b []byte) []byte {
+	z := make([]byte, len(b))
+	var carry uint
+	for i := range b {
+		s := uint(b[i]) + carry
+		if i < len(a) {
+			s += uint(a[i])
+		}
+		z[i] = byte(s)
+		carry = s >> 8
+	}
+	return z
+}
+
+// ladderBit returns the bit i of the little-endian k.
+func ladderBit(k []byte, i int) byte {
+	return (k[i/8] >> uint(i%8)) & 1
+}
+
+// cswap swaps the size bytes at a and b when swap is 1 and leaves them
+// unchanged when it is 0, with the same memory accesses in both cases. The
+// values must not contain Go pointers, which is the case of the backend
+// types.
+func cswap(a, b unsafe.Pointer, size uintptr, swap byte) {
+	mask := -swap
+	x := (*[1 << 30]byte)(a)[:size:size]
+	y := (*[1 << 30]byte)(b)[:size:size]
+	for i := range x {
+		t := mask & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
+
+// g1MulCT sets z = s * x with the ladder. It must be called while holding
+// the curve.
+func g1MulCT(z, x *g1, s *fr, r *big.Int) {
+	k := ladderScalarOf(s, r)
+	defer Zeroize(k)
+	var r0, r1 g1
+	r0 = *x
+	g1Add(&r1, x, x)
+	size := unsafe.Sizeof(r0)
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		b := ladderBit(k, i)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+		g1Add(&r1, &r0, &r1)
+		g1Add(&r0, &r0, &r0)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+	}
+	*z = r0
+	zeroize(unsafe.Pointer(&r0), size)
+	zeroize(unsafe.Pointer(&r1), size)
+}
+
+// g2MulCT sets z = s * x with the ladder. It must be called while holding
+// the curve.
+func g2MulCT(z, x *g2, s *fr, r *big.Int) {
+	k := ladderScalarOf(s, r)
+	defer Zeroize(k)
+	var r0, r1 g2
+	r0 = *x
+	g2Add(&r1, x, x)
+	size := unsafe.Sizeof(r0)
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		b := ladderBit(k, i)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+		g2Add(&r1, &r0, &r1)
+		g2Add(&r0, &r0, &r0)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+	}
+	*z = r0
+	zeroize(unsafe.Pointer(&r0), size)
+	zeroize(unsafe.Pointer(&r1), size)
+}
+
+// gtPowCT sets z = x^s with the ladder. It must be called while holding the
+// curve.
+func gtPowCT(z, x *gt, s *fr, r *big.Int) {
+	k := ladderScalarOf(s, r)
+	defer Zeroize(k)
+	var r0, r1 gt
+	r0 = *x
+	gtMul(&r1, x, x)
+	size := unsafe.Sizeof(r0)
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		b := ladderBit(k, i)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+		gtMul(&r1, &r0, &r1)
+		gtMul(&r0, &r0, &r0)
+		cswap(unsafe.Pointer(&r0), unsafe.Pointer(&r1), size, b)
+	}
+	*z = r0
+	zeroize(unsafe.Pointer(&r0), size)
+	zeroize(unsafe.Pointer(&r1), size)
+}
+
+// frInvCT sets z = 1/x as x^(r-2), with a sequence of multiplications that
+// only depends on r. It must be called while holding the curve.
+func frInvCT(z, x *fr, r *big.Int) {
+	e := new(big.Int).Sub(r, big.NewInt(2))
+	base := *x
+	acc := *x
+	acc.SetInt64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		frMul(&acc, &acc, &acc)
+		if e.Bit(i) == 1 {
+			frMul(&acc, &acc, &base)
+		}
+	}
+	*z = acc
+	zeroize(unsafe.Pointer(&acc), unsafe.Sizeof(acc))
+	zeroize(unsafe.Pointer(&base), unsafe.Sizeof(base))
+}
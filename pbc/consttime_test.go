@@ -0,0 +1,190 @@
+package pbc
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstantTimeMul(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Group{p.G1(), p.G2(), p.GT()} {
+		base := g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+		scalars := []abstract.Scalar{
+			g.Scalar().Zero(),
+			g.Scalar().One(),
+			g.Scalar().SetInt64(2),
+			g.Scalar().SetInt64(-1),
+			g.Scalar().Pick(random.Stream),
+		}
+		for _, s := range scalars {
+			ct := g.Point()
+			require.Nil(t, ct.(VarTimer).SetVarTime(false))
+			require.True(t, ct.Mul(base, s).Equal(g.Point().Mul(base, s)), "%s: %s", g, s)
+			require.True(t, ct.Mul(nil, s).Equal(g.Point().Mul(nil, s)), "%s: %s", g, s)
+
+			// a secret scalar is enough
+			sct := s.Clone()
+			require.Nil(t, sct.(VarTimer).SetVarTime(false))
+			require.True(t, g.Point().Mul(base, sct).Equal(g.Point().Mul(base, s)))
+		}
+	}
+
+	g := p.G1()
+	s := g.Scalar().Pick(random.Stream)
+	s2 := g.Scalar().Pick(random.Stream)
+	ct := g.Scalar()
+	require.Nil(t, ct.(VarTimer).SetVarTime(false))
+	require.True(t, ct.Inv(s).Equal(g.Scalar().Inv(s)))
+	require.True(t, ct.Div(s2, s).Equal(g.Scalar().Div(s2, s)))
+	require.True(t, ct.Clone().Inv(s).Equal(g.Scalar().Inv(s)))
+}
+
+func TestLadderBound(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		r := curveOrder(curve)
+		L := r.BitLen()
+		size := scalarSize(curve)
+		rMinusOne := new(big.Int).Sub(r, big.NewInt(1))
+		for _, k := range []*big.Int{big.NewInt(0), big.NewInt(1), rMinusOne, random.Int(r, random.Stream)} {
+			buff := make([]byte, size)
+			putLittleEndian(buff, k)
+			v := getLittleEndian(ladderScalar(buff, r))
+			require.Equal(t, uint(1), v.Bit(L))
+			require.Equal(t, L+1, v.BitLen())
+			require.Equal(t, 0, new(big.Int).Mod(v, r).Cmp(k))
+		}
+	}
+}
+
+// TestConstantTimeOps checks that the ladder runs the same number of
+// additions whatever the scalar.
+func TestConstantTimeOps(t *testing.T) {
+	p := NewPairingFp254BNb()
+	g := p.G1()
+	base := g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+
+	add := g1Add
+	defer func() { g1Add = add }()
+	var count int
+	g1Add = func(z, x, y *g1) {
+		count++
+		add(z, x, y)
+	}
+	var counts []int
+	for _, s := range []abstract.Scalar{
+		g.Scalar().Zero(),
+		g.Scalar().One(),
+		g.Scalar().SetInt64(-1),
+		g.Scalar().Pick(random.Stream),
+	} {
+		ct := g.Point()
+		ct.(VarTimer).SetVarTime(false)
+		count = 0
+		ct.Mul(base, s)
+		counts = append(counts, count)
+	}
+	for _, c := range counts {
+		require.Equal(t, counts[0], c)
+	}
+}
+
+// TestConstantTimeMultiMul checks that MultiMul falls back to the ladder when
+// one of its operands is secret.
+func TestConstantTimeMultiMul(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Group{p.G1(), p.G2()} {
+		points, scalars := randomTerms(g, 6)
+		exp := MultiMul(g, points, scalars)
+		scalars[3].(VarTimer).SetVarTime(false)
+		require.True(t, MultiMul(g, points, scalars).Equal(exp), "%s", g)
+		scalars[3].(VarTimer).SetVarTime(true)
+		points[5].(VarTimer).SetVarTime(false)
+		require.True(t, MultiMul(g, points, scalars).Equal(exp), "%s", g)
+	}
+
+	g := p.G1()
+	add := g1Add
+	defer func() { g1Add = add }()
+	var count int
+	g1Add = func(z, x, y *g1) {
+		count++
+		add(z, x, y)
+	}
+	points, scalars := randomTerms(g, 6)
+	scalars[0].(VarTimer).SetVarTime(false)
+	count = 0
+	MultiMul(g, points, scalars)
+	counted := count
+	for _, s := range scalars[1:] {
+		s.Zero()
+	}
+	count = 0
+	MultiMul(g, points, scalars)
+	require.Equal(t, counted, count)
+}
+
+// TestTimingVariance compares the running times of the constant time
+// multiplication by a fixed scalar of low weight and by random scalars with
+// Welch's t-test, like dudect does. Timings are noisy so it only runs when
+// PBC_TIMING is set:
+//
+//	PBC_TIMING=1 go test -run TimingVariance -v ./pbc
+func TestTimingVariance(t *testing.T) {
+	if os.Getenv("PBC_TIMING") == "" {
+		t.Skip("set PBC_TIMING to run the timing variance test")
+	}
+	p := NewPairingFp254BNb()
+	g := p.G1()
+	base := g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+	fixed := g.Scalar().SetInt64(1)
+
+	for _, varTime := range []bool{true, false} {
+		pt := g.Point()
+		pt.(VarTimer).SetVarTime(varTime)
+		var classes [2][]float64
+		for i := 0; i < 4000; i++ {
+			c := int(random.Bytes(1, random.Stream)[0] & 1)
+			s := fixed
+			if c == 1 {
+				s = g.Scalar().Pick(random.Stream)
+			}
+			start := time.Now()
+			pt.Mul(base, s)
+			classes[c] = append(classes[c], float64(time.Since(start)))
+		}
+		tstat := welch(classes[0], classes[1])
+		t.Logf("variable time %v: t = %.2f", varTime, tstat)
+		if !varTime && math.Abs(tstat) > 10 {
+			t.Errorf("constant time multiplication leaks timing: t = %.2f", tstat)
+		}
+	}
+}
+
+// welch returns Welch's t statistic of the two samples.
+func welch(a, b []float64) float64 {
+	meanVar := func(x []float64) (float64, float64) {
+		var m, v float64
+		for _, f := range x {
+			m += f
+		}
+		m /= float64(len(x))
+		for _, f := range x {
+			v += (f - m) * (f - m)
+		}
+		return m, v / float64(len(x)-1)
+	}
+	ma, va := meanVar(a)
+	mb, vb := meanVar(b)
+	return (ma - mb) / math.Sqrt(va/float64(len(a))+vb/float64(len(b)))
+}
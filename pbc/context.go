@@ -0,0 +1,26 @@
+package pbc
+
+import "fmt"
+
+// CurveMismatchError is the error raised when objects coming from two different
+// curves are used in the same operation. Since the abstract interfaces do not
+// return errors for arithmetic operations, points and scalars panic with it.
+type CurveMismatchError struct {
+	Expected int
+	Got      int
+}
+
+func (c *CurveMismatchError) Error() string {
+	return fmt.Sprintf("pbc: mixing objects from curve %s with curve %s",
+		curveName(c.Expected), curveName(c.Got))
+}
+
+// checkCurve panics with a CurveMismatchError if one of the given curves is
+// not the expected one.
+func checkCurve(expected int, curves ...int) {
+	for _, c := range curves {
+		if c != expected {
+			panic(&CurveMismatchError{Expected: expected, Got: c})
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package pbc
+
+import (
+	"unsafe"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// Destroyer is implemented by the scalars of this package. Destroy overwrites
+// the memory holding the value with zeros, so a secret does not outlive its
+// use until the garbage collector reclaims it. The scalar is zero afterwards
+// and can be reused.
+type Destroyer interface {
+	Destroy()
+}
+
+func (s *scalar) Destroy() {
+	defer withCurve(s.curve)()
+	zeroize(unsafe.Pointer(&s.fe), unsafe.Sizeof(s.fe))
+	s.fe = newFr(s.curve)
+	s.fe.SetInt64(0)
+}
+
+// DestroyScalar wipes s if it implements Destroyer and sets it to zero
+// otherwise. It does nothing on a nil scalar.
+func DestroyScalar(s abstract.Scalar) {
+	switch sc := s.(type) {
+	case nil:
+	case Destroyer:
+		sc.Destroy()
+	default:
+		sc.Zero()
+	}
+}
+
+// Zeroize overwrites buff with zeros, typically after decoding a secret out
+// of it.
+func Zeroize(buff []byte) {
+	for i := range buff {
+		buff[i] = 0
+	}
+}
+
+// zeroize overwrites the size bytes at p with zeros. Like cswap, it must only
+// be used on values without Go pointers.
+func zeroize(p unsafe.Pointer, size uintptr) {
+	Zeroize((*[1 << 30]byte)(p)[:size:size])
+}
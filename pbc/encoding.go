@@ -0,0 +1,336 @@
+package pbc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// The groups implement abstract.Encoding with a simple reflective binary
+// encoding:
+//  - points and scalars are written with their fixed size MarshalTo
+//  - booleans and integers are written big-endian, int and uint on 64 bits
+//  - strings, slices and byte slices are prefixed by their length on 32 bits
+//  - arrays and structs are the concatenation of their (exported) fields
+//  - pointers are prefixed by one byte telling whether they are nil
+// Interfaces of type abstract.Point and abstract.Scalar which are nil are
+// instantiated through the suite's New method when decoding.
+
+var pointType = reflect.TypeOf((*abstract.Point)(nil)).Elem()
+var scalarType = reflect.TypeOf((*abstract.Scalar)(nil)).Elem()
+
+// maxEncodedLen bounds the length prefixes accepted by the decoder, so a
+// corrupted length can not make it allocate arbitrary amounts of memory.
+const maxEncodedLen = 1 << 24
+
+var errEncodedLen = errors.New("pbc: encoded length too large")
+
+func (g *g1group) Read(r io.Reader, objs ...interface{}) error {
+	return suiteRead(g, r, objs)
+}
+
+func (g *g1group) Write(w io.Writer, objs ...interface{}) error {
+	return suiteWrite(w, objs)
+}
+
+func (g *g1group) New(t reflect.Type) interface{} {
+	return suiteNew(g, t)
+}
+
+func (g *g2group) Read(r io.Reader, objs ...interface{}) error {
+	return suiteRead(g, r, objs)
+}
+
+func (g *g2group) Write(w io.Writer, objs ...interface{}) error {
+	return suiteWrite(w, objs)
+}
+
+func (g *g2group) New(t reflect.Type) interface{} {
+	return suiteNew(g, t)
+}
+
+func (g *gtgroup) Read(r io.Reader, objs ...interface{}) error {
+	return suiteRead(g, r, objs)
+}
+
+func (g *gtgroup) Write(w io.Writer, objs ...interface{}) error {
+	return suiteWrite(w, objs)
+}
+
+func (g *gtgroup) New(t reflect.Type) interface{} {
+	return suiteNew(g, t)
+}
+
+// suiteNew returns a new point or scalar of the group if t is one of the
+// abstract.Point or abstract.Scalar interfaces and nil otherwise.
+func suiteNew(g abstract.Group, t reflect.Type) interface{} {
+	switch t {
+	case pointType:
+		return g.Point()
+	case scalarType:
+		return g.Scalar()
+	}
+	return nil
+}
+
+func suiteWrite(w io.Writer, objs []interface{}) error {
+	for i, obj := range objs {
+		if m, ok := obj.(abstract.Marshaling); ok {
+			if _, err := m.MarshalTo(w); err != nil {
+				return err
+			}
+			continue
+		}
+		v := reflect.ValueOf(obj)
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if err := writeValue(w, v); err != nil {
+			return fmt.Errorf("pbc: encoding object %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func suiteRead(c abstract.Constructor, r io.Reader, objs []interface{}) error {
+	for i, obj := range objs {
+		if m, ok := obj.(abstract.Marshaling); ok {
+			if _, err := m.UnmarshalFrom(r); err != nil {
+				return err
+			}
+			continue
+		}
+		v := reflect.ValueOf(obj)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return fmt.Errorf("pbc: decoding object %d: non-nil pointer expected", i)
+		}
+		if err := readValue(c, r, v.Elem()); err != nil {
+			return fmt.Errorf("pbc: decoding object %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func writeValue(w io.Writer, v reflect.Value) error {
+	if !v.IsValid() {
+		return errors.New("invalid value")
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return errors.New("nil interface " + v.Type().String())
+		}
+		m, ok := v.Interface().(abstract.Marshaling)
+		if !ok {
+			return errors.New("unsupported type " + v.Type().String())
+		}
+		_, err := m.MarshalTo(w)
+		return err
+	case reflect.Ptr:
+		if v.IsNil() {
+			_, err := w.Write([]byte{0})
+			return err
+		}
+		if _, err := w.Write([]byte{1}); err != nil {
+			return err
+		}
+		if m, ok := v.Interface().(abstract.Marshaling); ok {
+			_, err := m.MarshalTo(w)
+			return err
+		}
+		return writeValue(w, v.Elem())
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return binary.Write(w, binary.BigEndian, v.Interface())
+	case reflect.Int:
+		return binary.Write(w, binary.BigEndian, v.Int())
+	case reflect.Uint:
+		return binary.Write(w, binary.BigEndian, v.Uint())
+	case reflect.String:
+		if err := writeLen(w, v.Len()); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, v.String())
+		return err
+	case reflect.Slice:
+		if err := writeLen(w, v.Len()); err != nil {
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			_, err := w.Write(v.Bytes())
+			return err
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := writeValue(w, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue // unexported
+			}
+			if err := writeValue(w, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.New("unsupported type " + v.Type().String())
+}
+
+func readValue(c abstract.Constructor, r io.Reader, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			obj := c.New(v.Type())
+			if obj == nil {
+				return errors.New("no constructor for " + v.Type().String())
+			}
+			v.Set(reflect.ValueOf(obj))
+		}
+		m, ok := v.Interface().(abstract.Marshaling)
+		if !ok {
+			return errors.New("unsupported type " + v.Type().String())
+		}
+		_, err := m.UnmarshalFrom(r)
+		return err
+	case reflect.Ptr:
+		var flag [1]byte
+		if _, err := io.ReadFull(r, flag[:]); err != nil {
+			return err
+		}
+		switch flag[0] {
+		case 0:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		case 1:
+		default:
+			return errors.New("invalid pointer flag")
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if m, ok := v.Interface().(abstract.Marshaling); ok {
+			_, err := m.UnmarshalFrom(r)
+			return err
+		}
+		return readValue(c, r, v.Elem())
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return binary.Read(r, binary.BigEndian, v.Addr().Interface())
+	case reflect.Int:
+		var i int64
+		if err := binary.Read(r, binary.BigEndian, &i); err != nil {
+			return err
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint:
+		var i uint64
+		if err := binary.Read(r, binary.BigEndian, &i); err != nil {
+			return err
+		}
+		v.SetUint(i)
+		return nil
+	case reflect.String:
+		n, err := readLen(r)
+		if err != nil {
+			return err
+		}
+		buff, err := readBytes(r, n)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(buff))
+		return nil
+	case reflect.Slice:
+		n, err := readLen(r)
+		if err != nil {
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buff, err := readBytes(r, n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(buff)
+			return nil
+		}
+		// the elements are appended as they are decoded, so the length
+		// prefix alone does not allocate anything
+		s := reflect.MakeSlice(v.Type(), 0, 0)
+		for i := 0; i < n; i++ {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := readValue(c, r, e); err != nil {
+				return err
+			}
+			s = reflect.Append(s, e)
+		}
+		v.Set(s)
+		return nil
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := readValue(c, r, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := readValue(c, r, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.New("unsupported type " + v.Type().String())
+}
+
+func writeLen(w io.Writer, n int) error {
+	if n > maxEncodedLen {
+		return errEncodedLen
+	}
+	return binary.Write(w, binary.BigEndian, uint32(n))
+}
+
+// readChunk is the size of the reads of readBytes.
+const readChunk = 1 << 12
+
+// readBytes reads n bytes from r. The buffer grows with the bytes actually
+// read instead of being allocated at once from an untrusted length.
+func readBytes(r io.Reader, n int) ([]byte, error) {
+	buff := make([]byte, 0)
+	for len(buff) < n {
+		m := n - len(buff)
+		if m > readChunk {
+			m = readChunk
+		}
+		buff = append(buff, make([]byte, m)...)
+		if _, err := io.ReadFull(r, buff[len(buff)-m:]); err != nil {
+			return nil, err
+		}
+	}
+	return buff, nil
+}
+
+func readLen(r io.Reader) (int, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return 0, err
+	}
+	if n > maxEncodedLen {
+		return 0, errEncodedLen
+	}
+	return int(n), nil
+}
@@ -0,0 +1,78 @@
+package pbc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+)
+
+type encInner struct {
+	I int
+	V abstract.Scalar
+}
+
+type encOuter struct {
+	Index   uint32
+	Status  bool
+	Name    string
+	Buff    []byte
+	Points  []abstract.Point
+	Inner   *encInner
+	Nil     *encInner
+	Inners  []encInner
+	private int
+}
+
+func TestSuiteEncoding(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Suite{p.G1(), p.G2(), p.GT()} {
+		s := g.Scalar().Pick(random.Stream)
+		msg := &encOuter{
+			Index:  42,
+			Status: true,
+			Name:   g.String(),
+			Buff:   []byte("hello"),
+			Points: []abstract.Point{g.Point().Base(), g.Point().Mul(nil, s)},
+			Inner:  &encInner{I: 3, V: s},
+			Inners: []encInner{{I: 1, V: g.Scalar().One()}},
+		}
+		var b bytes.Buffer
+		require.Nil(t, g.Write(&b, msg, s))
+
+		decoded := new(encOuter)
+		s2 := g.Scalar()
+		require.Nil(t, g.Read(bytes.NewReader(b.Bytes()), decoded, s2))
+		require.Equal(t, msg.Index, decoded.Index)
+		require.Equal(t, msg.Status, decoded.Status)
+		require.Equal(t, msg.Name, decoded.Name)
+		require.Equal(t, msg.Buff, decoded.Buff)
+		require.Len(t, decoded.Points, 2)
+		for i := range msg.Points {
+			require.True(t, msg.Points[i].Equal(decoded.Points[i]))
+		}
+		require.Equal(t, msg.Inner.I, decoded.Inner.I)
+		require.True(t, msg.Inner.V.Equal(decoded.Inner.V))
+		require.Nil(t, decoded.Nil)
+		require.Len(t, decoded.Inners, 1)
+		require.True(t, decoded.Inners[0].V.Equal(g.Scalar().One()))
+		require.True(t, s.Equal(s2))
+
+		// truncated input
+		require.NotNil(t, g.Read(bytes.NewReader(b.Bytes()[:b.Len()/2]), new(encOuter)))
+		// not a pointer
+		require.NotNil(t, g.Read(bytes.NewReader(b.Bytes()), encOuter{}))
+	}
+}
+
+func TestSuiteNew(t *testing.T) {
+	p := NewPairingFp254BNb()
+	g := p.G1()
+	_, ok := g.New(pointType).(abstract.Point)
+	require.True(t, ok)
+	_, ok = g.New(scalarType).(abstract.Scalar)
+	require.True(t, ok)
+	require.Nil(t, g.New(nil))
+}
@@ -0,0 +1,156 @@
+package pbc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// EnvelopeVersion is the version of the envelopes written by Seal.
+const EnvelopeVersion = 1
+
+// envelopeHeaderLen is the size of the header of an envelope:
+//
+//	version | curve | group | point format
+//
+// one byte each, followed by the objects encoded by the group.
+const envelopeHeaderLen = 4
+
+// GroupID identifies one of the groups of a pairing in an envelope.
+type GroupID byte
+
+// The groups of a pairing.
+const (
+	GroupG1 GroupID = 1
+	GroupG2 GroupID = 2
+	GroupGT GroupID = 3
+)
+
+func (id GroupID) String() string {
+	switch id {
+	case GroupG1:
+		return "G1"
+	case GroupG2:
+		return "G2"
+	case GroupGT:
+		return "GT"
+	default:
+		return fmt.Sprintf("GroupID(%d)", byte(id))
+	}
+}
+
+// Reasons for which an envelope can be rejected by Open and OpenAs, wrapped
+// in a *DecodeError.
+var (
+	ErrEnvelopeVersion  = errors.New("unsupported envelope version")
+	ErrEnvelopeCurve    = errors.New("unsupported curve")
+	ErrEnvelopeGroup    = errors.New("unknown group")
+	ErrEnvelopeFormat   = errors.New("unknown point format")
+	ErrEnvelopeMismatch = errors.New("envelope of another group")
+)
+
+// Seal encodes objs with the encoding of g, which must be one of the groups of
+// a Pairing, after a header telling the envelope version, the curve, the
+// group and the point format, so the receiver does not need to know them in
+// advance.
+func Seal(g abstract.Suite, objs ...interface{}) ([]byte, error) {
+	curve, id, format, err := groupInfo(g)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.Write([]byte{EnvelopeVersion, byte(curve), byte(id), byte(format)})
+	if err := g.Write(&b, objs...); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Open decodes the objects of the envelope into objs, with the group given by
+// its header which is returned.
+func Open(buff []byte, objs ...interface{}) (abstract.Suite, error) {
+	if len(buff) < envelopeHeaderLen {
+		return nil, &DecodeError{Group: "envelope", Reason: ErrInvalidLength}
+	}
+	if buff[0] != EnvelopeVersion {
+		return nil, &DecodeError{Group: "envelope", Reason: ErrEnvelopeVersion}
+	}
+	curve, id, format := int(buff[1]), GroupID(buff[2]), PointFormat(buff[3])
+	if !Supported(curve) {
+		return nil, &DecodeError{Group: "envelope", Reason: ErrEnvelopeCurve}
+	}
+	if format != Compressed && format != Uncompressed {
+		return nil, &DecodeError{Group: "envelope", Reason: ErrEnvelopeFormat}
+	}
+	p := envelopePairing(curve, format)
+	var g abstract.Suite
+	switch id {
+	case GroupG1:
+		g = p.G1()
+	case GroupG2:
+		g = p.G2()
+	case GroupGT:
+		g = p.GT()
+	default:
+		return nil, &DecodeError{Group: "envelope", Reason: ErrEnvelopeGroup}
+	}
+	if err := g.Read(bytes.NewReader(buff[envelopeHeaderLen:]), objs...); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// OpenAs decodes the envelope like Open but only if it was sealed with the
+// curve and group of g, returning a *DecodeError with ErrEnvelopeMismatch
+// otherwise. The point format may differ.
+func OpenAs(g abstract.Suite, buff []byte, objs ...interface{}) error {
+	curve, id, _, err := groupInfo(g)
+	if err != nil {
+		return err
+	}
+	if len(buff) >= envelopeHeaderLen && buff[0] == EnvelopeVersion &&
+		(int(buff[1]) != curve || GroupID(buff[2]) != id) {
+		return &DecodeError{Group: g.String(), Reason: ErrEnvelopeMismatch}
+	}
+	_, err = Open(buff, objs...)
+	return err
+}
+
+// groupInfo returns the curve, identifier and point format of a group of a
+// Pairing.
+func groupInfo(g abstract.Suite) (int, GroupID, PointFormat, error) {
+	switch gr := g.(type) {
+	case *g1group:
+		return gr.curve, GroupG1, gr.format, nil
+	case *g2group:
+		return gr.curve, GroupG2, gr.format, nil
+	case *gtgroup:
+		return gr.curve, GroupGT, Compressed, nil
+	default:
+		return 0, 0, 0, errors.New("pbc: not a pbc group")
+	}
+}
+
+var envelopePairings = struct {
+	sync.Mutex
+	m map[[2]int]*Pairing
+}{m: make(map[[2]int]*Pairing)}
+
+// envelopePairing returns the pairing used to decode the envelopes of the
+// curve with the given point format, so the generators and tables are
+// computed once.
+func envelopePairing(curve int, format PointFormat) *Pairing {
+	envelopePairings.Lock()
+	defer envelopePairings.Unlock()
+	key := [2]int{curve, int(format)}
+	if p, ok := envelopePairings.m[key]; ok {
+		return p
+	}
+	p := NewPairing(curve)
+	p.SetPointFormat(format)
+	envelopePairings.m[key] = p
+	return p
+}
@@ -0,0 +1,90 @@
+package pbc
+
+import (
+	"encoding/binary"
+	"runtime"
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	p := NewPairingFp254BNb()
+	p.SetPointFormat(Uncompressed)
+	for _, g := range []abstract.Suite{p.G1(), p.G2(), p.GT()} {
+		s := g.Scalar().Pick(random.Stream)
+		msg := &encInner{I: 7, V: s}
+		pt := g.Point().Mul(nil, s)
+		buff, err := Seal(g, msg, pt)
+		require.Nil(t, err)
+
+		decoded := new(encInner)
+		decodedPt := g.Point()
+		g2, err := Open(buff, decoded, decodedPt)
+		require.Nil(t, err)
+		require.Equal(t, g.String(), g2.String())
+		require.Equal(t, msg.I, decoded.I)
+		require.True(t, s.Equal(decoded.V))
+		require.True(t, pt.Equal(decodedPt))
+
+		require.Nil(t, OpenAs(g, buff, new(encInner)))
+	}
+
+	buff, err := Seal(p.G1(), p.G1().Point().Base())
+	require.Nil(t, err)
+	requireReason(t, OpenAs(p.G2(), buff, p.G2().Point()), ErrEnvelopeMismatch)
+
+	bad := append([]byte{}, buff...)
+	bad[0] = EnvelopeVersion + 1
+	_, err = Open(bad, p.G1().Point())
+	requireReason(t, err, ErrEnvelopeVersion)
+
+	bad = append([]byte{}, buff...)
+	bad[1] = 42
+	_, err = Open(bad, p.G1().Point())
+	requireReason(t, err, ErrEnvelopeCurve)
+
+	bad = append([]byte{}, buff...)
+	bad[2] = 4
+	_, err = Open(bad, p.G1().Point())
+	requireReason(t, err, ErrEnvelopeGroup)
+
+	bad = append([]byte{}, buff...)
+	bad[3] = 2
+	_, err = Open(bad, p.G1().Point())
+	requireReason(t, err, ErrEnvelopeFormat)
+
+	_, err = Open(buff[:2])
+	requireReason(t, err, ErrInvalidLength)
+	_, err = Open(buff[:len(buff)-1], p.G1().Point())
+	require.NotNil(t, err)
+}
+
+func TestEnvelopeLengthPrefix(t *testing.T) {
+	p := NewPairingFp254BNb()
+	g := p.G1()
+	header, err := Seal(g)
+	require.Nil(t, err)
+	// a short envelope announcing a slice of almost maxEncodedLen elements
+	buff := make([]byte, len(header)+4+8)
+	copy(buff, header)
+	binary.BigEndian.PutUint32(buff[len(header):], maxEncodedLen-1)
+
+	objs := []interface{}{
+		&struct{ Buff []byte }{},
+		&struct{ Name string }{},
+		&struct{ Points []abstract.Point }{},
+		&struct{ Inners []encInner }{},
+	}
+	for _, obj := range objs {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		require.NotNil(t, OpenAs(g, buff, obj))
+		runtime.ReadMemStats(&after)
+		require.True(t, after.TotalAlloc-before.TotalAlloc < 1<<20,
+			"%T allocated %d bytes", obj, after.TotalAlloc-before.TotalAlloc)
+	}
+}
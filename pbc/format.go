@@ -0,0 +1,174 @@
+package pbc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PointFormat selects how points of G1 and G2 are marshalled.
+type PointFormat int
+
+const (
+	// Compressed points carry only the x-coordinate, with the parity of y and
+	// the point at infinity flagged in the most significant bits. It is the
+	// native format of the backends and the default.
+	Compressed PointFormat = iota
+	// Uncompressed points are the concatenation of the little-endian affine
+	// coordinates x and y. They are twice as large but decode without a
+	// square root. The point at infinity is all zeros.
+	Uncompressed
+)
+
+func (f PointFormat) String() string {
+	switch f {
+	case Compressed:
+		return "compressed"
+	case Uncompressed:
+		return "uncompressed"
+	default:
+		return "unknown"
+	}
+}
+
+// FormatMarshaler is implemented by the points of G1 and G2. MarshalFormat
+// encodes a point in the given format, whatever the format of its suite, for
+// the encodings which must not depend on it such as the ones that are signed
+// or used as keys.
+type FormatMarshaler interface {
+	MarshalFormat(f PointFormat) ([]byte, error)
+}
+
+// FormatUnmarshaler is implemented by the points of G1 and G2. UnmarshalFormat
+// decodes a point in either format, whatever the format of its suite, and
+// returns the one it read.
+type FormatUnmarshaler interface {
+	UnmarshalFormat(buff []byte) (PointFormat, error)
+}
+
+var errPointFormat = errors.New("pbc: invalid point format")
+
+// SetPointFormat selects the format used to marshal the points of G1 and G2
+// created from now on by this pairing. Points decode both formats regardless.
+func (p *Pairing) SetPointFormat(f PointFormat) {
+	if f != Compressed && f != Uncompressed {
+		panic(errPointFormat)
+	}
+	p.g1.format = f
+	p.g2.format = f
+}
+
+// PointFormat returns the format used to marshal points of G1 and G2.
+func (p *Pairing) PointFormat() PointFormat {
+	return p.g1.format
+}
+
+// coordSize returns the size in bytes of an element of the base field.
+func coordSize(curve int) int {
+	return opUnitSize(curve) * 8
+}
+
+// pointSize returns the size of a point made of coords base field elements
+// in the given format.
+func pointSize(curve, coords int, f PointFormat) int {
+	if f == Uncompressed {
+		return 2 * coords * coordSize(curve)
+	}
+	return coords * coordSize(curve)
+}
+
+// formatOf returns the format of buff given its length.
+func formatOf(curve, coords int, buff []byte) (PointFormat, error) {
+	switch len(buff) {
+	case pointSize(curve, coords, Compressed):
+		return Compressed, nil
+	case pointSize(curve, coords, Uncompressed):
+		return Uncompressed, nil
+	}
+	return 0, errPointFormat
+}
+
+type stringer interface {
+	GetString(base int) string
+	SetString(s string, base int) error
+}
+
+// marshalUncompressed writes the affine coordinates of p taken from its
+// string representation "1 x0 x1 ... y0 y1 ..." or "0" for infinity.
+func marshalUncompressed(p stringer, curve, coords int) ([]byte, error) {
+	size := coordSize(curve)
+	buff := make([]byte, 2*coords*size)
+	parts := strings.Fields(p.GetString(16))
+	if len(parts) == 1 && parts[0] == "0" {
+		return buff, nil
+	}
+	if len(parts) != 1+2*coords || parts[0] != "1" {
+		return nil, errPointFormat
+	}
+	for i, s := range parts[1:] {
+		b, ok := new(big.Int).SetString(s, 16)
+		if !ok || b.BitLen() > size*8 {
+			return nil, errPointFormat
+		}
+		putLittleEndian(buff[i*size:(i+1)*size], b)
+	}
+	return buff, nil
+}
+
+// unmarshalUncompressed is the inverse of marshalUncompressed. It returns
+// false if buff is not the encoding marshalUncompressed gives for the point,
+// e.g. because a coordinate is not reduced.
+func unmarshalUncompressed(p stringer, curve, coords int, buff []byte) (canonical bool, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			canonical = false
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	size := coordSize(curve)
+	if len(buff) != 2*coords*size {
+		return false, errPointFormat
+	}
+	if isZero(buff) {
+		return true, p.SetString("0", 16)
+	}
+	parts := []string{"1"}
+	for i := 0; i < 2*coords; i++ {
+		b := getLittleEndian(buff[i*size : (i+1)*size])
+		parts = append(parts, b.Text(16))
+	}
+	if err := p.SetString(strings.Join(parts, " "), 16); err != nil {
+		return false, err
+	}
+	out, err := marshalUncompressed(p, curve, coords)
+	return bytes.Equal(out, buff), err
+}
+
+func putLittleEndian(buff []byte, b *big.Int) {
+	be := b.Bytes()
+	for i := range buff {
+		buff[i] = 0
+	}
+	for i, c := range be {
+		buff[len(be)-1-i] = c
+	}
+}
+
+func getLittleEndian(buff []byte) *big.Int {
+	be := make([]byte, len(buff))
+	for i, c := range buff {
+		be[len(buff)-1-i] = c
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func isZero(buff []byte) bool {
+	for _, c := range buff {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
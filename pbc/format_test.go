@@ -0,0 +1,65 @@
+package pbc
+
+import (
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointFormat(t *testing.T) {
+	compressed := NewPairingFp254BNb()
+	uncompressed := NewPairingFp254BNb()
+	uncompressed.SetPointFormat(Uncompressed)
+	require.Equal(t, Compressed, compressed.PointFormat())
+	require.Equal(t, Uncompressed, uncompressed.PointFormat())
+
+	groups := []struct {
+		c, u abstract.Group
+	}{
+		{compressed.G1(), uncompressed.G1()},
+		{compressed.G2(), uncompressed.G2()},
+	}
+	for _, g := range groups {
+		require.Equal(t, 2*g.c.PointLen(), g.u.PointLen())
+
+		s := g.c.Scalar().Pick(random.Stream)
+		p := g.c.Point().Mul(nil, s)
+		for _, src := range []abstract.Group{g.c, g.u} {
+			for _, q := range []abstract.Point{src.Point().Mul(nil, s), src.Point().Null()} {
+				buff, err := q.MarshalBinary()
+				require.Nil(t, err)
+				require.Equal(t, src.PointLen(), len(buff))
+
+				// both suites decode both formats
+				for _, dst := range []abstract.Group{g.c, g.u} {
+					r := dst.Point()
+					f, err := r.(FormatUnmarshaler).UnmarshalFormat(buff)
+					require.Nil(t, err)
+					require.Equal(t, src.PointLen() == g.u.PointLen(), f == Uncompressed)
+					require.True(t, r.Equal(q))
+
+					// q marshals in the format of dst as well
+					dstFormat := Compressed
+					if dst == g.u {
+						dstFormat = Uncompressed
+					}
+					exp, err := r.MarshalBinary()
+					require.Nil(t, err)
+					fbuff, err := q.(FormatMarshaler).MarshalFormat(dstFormat)
+					require.Nil(t, err)
+					require.Equal(t, exp, fbuff)
+				}
+			}
+			require.True(t, src.Point().Mul(nil, s).Equal(p))
+		}
+
+		r := g.c.Point()
+		require.NotNil(t, r.UnmarshalBinary(make([]byte, g.c.PointLen()+1)))
+		buff := make([]byte, g.u.PointLen())
+		buff[0] = 1
+		require.NotNil(t, r.UnmarshalBinary(buff))
+	}
+}
@@ -3,13 +3,10 @@ package pbc
 import (
 	"crypto/cipher"
 	"crypto/sha256"
+	"errors"
 	"hash"
-	"io"
-	"reflect"
 	"strings"
-	"sync"
 
-	"github.com/dfinity/go-dfinity-crypto/bls"
 	"gopkg.in/dedis/crypto.v0/abstract"
 
 	"gopkg.in/dedis/crypto.v0/cipher/sha3"
@@ -30,6 +27,11 @@ type PointGT interface {
 	// Compute the pairing of two points p1 and p2,
 	// which must be in the associated groups G1 and G2 respectively.
 	Pairing(p1, p2 abstract.Point) abstract.Point
+
+	// Compute the product of the pairings of p1s[i] and p2s[i], which must
+	// be in G1 and G2 respectively. It is cheaper than multiplying the
+	// results of Pairing since the final exponentiation is shared.
+	PairingProduct(p1s, p2s []abstract.Point) abstract.Point
 }
 
 // PairingSuite represents the basic functionalities needed to use pairing based
@@ -42,15 +44,18 @@ type PairingSuite interface {
 
 type g1group struct {
 	common
-	curve int
+	format PointFormat
+	base   *baseG1
 }
 type g2group struct {
 	common
-	curve int
+	format PointFormat
+	base   *baseG2
 }
 type gtgroup struct {
 	common
-	p *Pairing
+	p    *Pairing
+	base baseGT
 }
 
 // A Pairing object represents a pairing-based cryptography environment,
@@ -67,27 +72,37 @@ type Pairing struct {
 	gt    gtgroup
 }
 
-var l sync.Mutex
-
-// NewPairing returns a new initialized curve.
-// XXX It is currently UNDEFINED to declare multiple pairing since the C lib uses a global
-// variable underneath.
+// NewPairing returns a new initialized curve. Pairings on different curves can
+// be used concurrently, but objects of one curve can not be mixed with
+// objects of another curve.
 func NewPairing(curve int) *Pairing {
-	ok := curve == CurveFp254BNb || curve == CurveFp382_1 || curve == CurveFp382_2
-	if !ok {
+	if !Supported(curve) {
 		panic("pairing: unsupported curve")
 	}
-	l.Lock()
-	defer l.Unlock()
-	bls.Init(curve)
+	// initialize the library right away so a faulty setup fails early
+	withCurve(curve)()
 
 	p := &Pairing{curve: curve}
+	pl := new(pools)
 	p.g1.curve = curve
+	p.g1.pools = pl
+	p.g1.base = newBaseG1(curve)
 	p.g2.curve = curve
+	p.g2.pools = pl
+	p.g2.base = newBaseG2(curve)
+	p.gt.curve = curve
+	p.gt.pools = pl
 	p.gt.p = p
+	p.gt.base.curve = curve
 	return p
 }
 
+// Supported returns true if the curve is implemented by the Backend this
+// package was built with.
+func Supported(curve int) bool {
+	return supported(curve)
+}
+
 func NewPairingFp254BNb() *Pairing {
 	return NewPairing(CurveFp254BNb)
 }
@@ -100,6 +115,25 @@ func NewPairingFp382_2() *Pairing {
 	return NewPairing(CurveFp382_2)
 }
 
+// ErrUnsupportedCurve is returned when the backend does not implement the
+// curve, such as BLS12-381 with the default cgo backend.
+var ErrUnsupportedCurve = errors.New("pbc: curve not supported by the " + Backend + " backend")
+
+// NewPairingBLS12_381 returns a pairing on BLS12-381. Only the pure Go backend
+// implements it, so it returns ErrUnsupportedCurve unless the package is built
+// with the purego tag.
+func NewPairingBLS12_381() (*Pairing, error) {
+	if !Supported(CurveBLS12_381) {
+		return nil, ErrUnsupportedCurve
+	}
+	return NewPairing(CurveBLS12_381), nil
+}
+
+// Curve returns the identifier of the curve used by this pairing.
+func (p *Pairing) Curve() int {
+	return p.curve
+}
+
 func (p *Pairing) G1() abstract.Suite {
 	return &p.g1
 }
@@ -128,7 +162,7 @@ func (g *g1group) PointLen() int {
 }
 
 func (g *g1group) Point() abstract.Point {
-	return newPointG1(generator(g.curve, 0))
+	return g.newPoint()
 }
 
 func (g *g2group) String() string {
@@ -140,11 +174,11 @@ func (g *g2group) PointLen() int {
 }
 
 func (g *g2group) Point() abstract.Point {
-	return newPointG2(generator(g.curve, 1))
+	return g.newPoint()
 }
 
 func (g *gtgroup) String() string {
-	return curveName(g.p.curve) + "_GT"
+	return curveName(g.curve) + "_GT"
 }
 
 func (g *gtgroup) PointLen() int {
@@ -152,14 +186,17 @@ func (g *gtgroup) PointLen() int {
 }
 
 func (g *gtgroup) Point() abstract.Point {
-	return newPointGT(g.p)
+	return g.newPoint()
 }
 
 func (g *gtgroup) PointGT() PointGT {
 	return g.Point().(PointGT)
 }
 
-type common struct{}
+type common struct {
+	curve int
+	pools *pools
+}
 
 func (c *common) Hash() hash.Hash {
 	return sha256.New()
@@ -174,27 +211,15 @@ func (c *common) PrimeOrder() bool {
 }
 
 func (c *common) ScalarLen() int {
-	return bls.GetOpUnitSize() * 8
+	return scalarSize(c.curve)
 }
 
 func (c *common) Scalar() abstract.Scalar {
-	return NewScalar()
-}
-
-func (c *common) Read(r io.Reader, objs ...interface{}) error {
-	panic("Not implemented")
-}
-
-func (c *common) Write(w io.Writer, objs ...interface{}) error {
-	panic("not implemented")
-}
-
-func (c *common) New(t reflect.Type) interface{} {
-	panic("not implemented")
+	return c.newScalar()
 }
 
 func (c *common) NewKey(r cipher.Stream) abstract.Scalar {
-	return NewScalar().Pick(r)
+	return c.newScalar().Pick(r)
 }
 
 func curveName(curve int) string {
@@ -205,24 +230,60 @@ func curveName(curve int) string {
 		return "Fp382_1"
 	case CurveFp382_2:
 		return "Fp382_2"
+	case CurveBLS12_381:
+		return "BLS12_381"
 	default:
 		panic("pairing curve unknown")
 	}
 }
 
 func Curve(name string) int {
+	curve, ok := curveByName(name)
+	if !ok {
+		panic("pairing curve unknown")
+	}
+	return curve
+}
+
+// curveByName is Curve without the panic.
+func curveByName(name string) (int, bool) {
 	switch strings.ToLower(name) {
 	case "fp254nb":
-		return CurveFp254BNb
+		return CurveFp254BNb, true
 	case "fp382_1":
-		return CurveFp382_1
+		return CurveFp382_1, true
 	case "fp382_2":
-		return CurveFp382_2
+		return CurveFp382_2, true
+	case "bls12_381":
+		return CurveBLS12_381, true
+	default:
+		return 0, false
+	}
+}
+
+// opUnitSize returns the number of 64-bit words used by the C library to
+// represent an element of the base field of the curve.
+func opUnitSize(curve int) int {
+	switch curve {
+	case CurveFp254BNb:
+		return 4
+	case CurveFp382_1, CurveFp382_2, CurveBLS12_381:
+		return 6
 	default:
 		panic("pairing curve unknown")
 	}
 }
 
+// scalarSize returns the size in bytes of a scalar of the curve. It is the
+// size of an element of the base field, except on BLS12-381 whose order only
+// takes 255 bits.
+func scalarSize(curve int) int {
+	if curve == CurveBLS12_381 {
+		return 32
+	}
+	return opUnitSize(curve) * 8
+}
+
 func generator(curve, group int) string {
 	var gens [2]string
 	switch curve {
@@ -235,6 +296,9 @@ func generator(curve, group int) string {
 	case CurveFp382_2:
 		gens[0] = Fp382_2_G1_Base_Str
 		gens[1] = Fp382_2_G2_Base_Str
+	case CurveBLS12_381:
+		gens[0] = BLS12_381_G1_Base_Str
+		gens[1] = BLS12_381_G2_Base_Str
 	default:
 		panic("pairing curve unknown")
 	}
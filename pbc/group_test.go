@@ -2,8 +2,11 @@ package pbc
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
 	"gopkg.in/dedis/crypto.v0/test"
 
 	"github.com/stretchr/testify/require"
@@ -44,7 +47,15 @@ func printSeed(name string, h hashmap, t *testing.T) {
 	fmt.Println(name + " : " + h.GetString(16))
 }
 
+// requireCurve skips the test if the backend does not implement the curve.
+func requireCurve(t *testing.T, curve int) {
+	if !Supported(curve) {
+		t.Skipf("curve %s not supported by the %s backend", curveName(curve), Backend)
+	}
+}
+
 func TestG2(t *testing.T) {
+	requireCurve(t, CurveFp382_2)
 	var p0 = NewPairingFp382_2()
 	g2 := p0.GT()
 	q1 := g2.Point().Base()  // q1 = base
@@ -66,20 +77,218 @@ func TestG2(t *testing.T) {
 func TestP0(t *testing.T) {
 	var p0 = NewPairingFp254BNb()
 	test.TestGroup(p0.G1())
-	//test.TestGroup(p0.G2())
-	//test.GroupTest(p0.GT())
+	test.TestGroup(p0.G2())
+	test.TestGroup(p0.GT())
 }
 
 func TestP1(t *testing.T) {
+	requireCurve(t, CurveFp382_1)
 	var p1 = NewPairingFp382_1()
 	test.TestGroup(p1.G1())
-	//test.GroupTest(p1.G2())
-	//test.GroupTest(p1.GT())
+	test.TestGroup(p1.G2())
+	test.TestGroup(p1.GT())
 }
 
 func TestP2(t *testing.T) {
-	//var p2 = NewPairingFp382_2()
-	//test.GroupTest(p2.G2())
-	//test.GroupTest(p2.G1())
-	//test.GroupTest(p2.GT())
+	requireCurve(t, CurveFp382_2)
+	var p2 = NewPairingFp382_2()
+	test.TestGroup(p2.G1())
+	test.TestGroup(p2.G2())
+	test.TestGroup(p2.GT())
+}
+
+func TestBLS12_381(t *testing.T) {
+	p, err := NewPairingBLS12_381()
+	if !Supported(CurveBLS12_381) {
+		require.Equal(t, ErrUnsupportedCurve, err)
+		require.Nil(t, p)
+	}
+	requireCurve(t, CurveBLS12_381)
+	require.Nil(t, err)
+	test.TestGroup(p.G1())
+	test.TestGroup(p.G2())
+	test.TestGroup(p.GT())
+}
+
+func TestGroupSemantics(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		p := NewPairing(curve)
+		for _, g := range []abstract.Group{p.G1(), p.G2(), p.GT()} {
+			base := g.Point().Base()
+			null := g.Point().Null()
+			require.True(t, g.Point().Add(base, null).Equal(base), "%s", g)
+			require.True(t, g.Point().Add(base, g.Point().Neg(base)).Equal(null), "%s", g)
+			require.True(t, g.Point().Sub(null, base).Equal(g.Point().Neg(base)), "%s", g)
+			require.True(t, g.Point().Mul(base, g.Scalar().Zero()).Equal(null), "%s", g)
+
+			c := base.(interface {
+				Clone() abstract.Point
+			}).Clone()
+			require.True(t, c.Equal(base))
+			c.Add(c, base)
+			require.False(t, c.Equal(base), "%s: clone is not independent", g)
+
+			for _, pt := range []abstract.Point{null, base, c} {
+				buff, err := pt.MarshalBinary()
+				require.Nil(t, err)
+				require.Len(t, buff, g.PointLen())
+				dec := g.Point()
+				require.Nil(t, dec.UnmarshalBinary(buff), "%s", g)
+				require.True(t, dec.Equal(pt), "%s", g)
+			}
+
+			r, _ := g.Point().Pick(nil, random.Stream)
+			require.False(t, r.Equal(null))
+		}
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	p := NewPairingFp254BNb()
+	g1 := p.G1()
+	msg := []byte("The quick brown fox jumps over the lazy dog")
+	for _, f := range []PointFormat{Compressed, Uncompressed} {
+		p.SetPointFormat(f)
+		pt, rem := g1.Point().Pick(msg, random.Stream)
+		data, err := pt.Data()
+		require.Nil(t, err)
+		require.Equal(t, g1.Point().PickLen(), len(data))
+		require.Equal(t, msg, append(data, rem...))
+	}
+}
+
+// TestNoEmbed checks the groups which can not embed data: G2, GT, and G1 when
+// it has a cofactor. PickLen is 0, Pick leaves all the data as the remainder
+// and returns a random element of the group, and Data is empty.
+func TestNoEmbed(t *testing.T) {
+	msg := []byte("The quick brown fox jumps over the lazy dog")
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		p := NewPairing(curve)
+		groups := []abstract.Suite{p.G2(), p.GT()}
+		if g1HasCofactor(curve) {
+			groups = append(groups, p.G1())
+		}
+		for _, g := range groups {
+			require.Equal(t, 0, g.Point().PickLen(), "%s", g)
+			pt, rem := g.Point().Pick(msg, random.Stream)
+			require.Equal(t, msg, rem, "%s", g)
+			data, err := pt.Data()
+			require.Nil(t, err)
+			require.Empty(t, data, "%s", g)
+
+			require.False(t, pt.Equal(g.Point().Null()), "%s", g)
+			pt2, _ := g.Point().Pick(msg, random.Stream)
+			require.False(t, pt.Equal(pt2), "%s", g)
+			buff, err := pt.MarshalBinary()
+			require.Nil(t, err)
+			dec := g.Point()
+			require.Nil(t, dec.UnmarshalBinary(buff), "%s", g)
+			require.True(t, dec.Equal(pt), "%s", g)
+		}
+	}
+}
+
+func TestMultipleCurves(t *testing.T) {
+	var pairings []*Pairing
+	for _, c := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if Supported(c) {
+			pairings = append(pairings, NewPairing(c))
+		}
+	}
+	var wg sync.WaitGroup
+	for _, p := range pairings {
+		wg.Add(1)
+		go func(p *Pairing) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				sk := p.G2().Scalar().Pick(random.Stream)
+				pk := p.G2().Point().Mul(nil, sk)
+				buff, err := pk.MarshalBinary()
+				require.Nil(t, err)
+				require.Len(t, buff, pk.MarshalSize())
+				pk2 := p.G2().Point()
+				require.Nil(t, pk2.UnmarshalBinary(buff))
+				require.True(t, pk.Equal(pk2))
+
+				// e(sk * G1, G2) == e(G1, sk * G2)
+				g1 := p.G1().Point().Mul(nil, sk)
+				left := p.GT().PointGT().Pairing(g1, p.G2().Point().Base())
+				right := p.GT().PointGT().Pairing(p.G1().Point().Base(), pk)
+				require.True(t, left.Equal(right))
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+func TestCurveMismatch(t *testing.T) {
+	requireCurve(t, CurveFp382_1)
+	p0 := NewPairingFp254BNb()
+	p1 := NewPairingFp382_1()
+
+	mismatch := func(fn func()) {
+		defer func() {
+			e := recover()
+			require.NotNil(t, e)
+			_, ok := e.(*CurveMismatchError)
+			require.True(t, ok)
+		}()
+		fn()
+	}
+
+	a := p0.G1().Point().Base()
+	b := p1.G1().Point().Base()
+	mismatch(func() { p0.G1().Point().Add(a, b) })
+	mismatch(func() { p0.G1().Point().Mul(a, p1.G1().Scalar().One()) })
+	mismatch(func() { p0.G1().Scalar().Add(p0.G1().Scalar().One(), p1.G1().Scalar().One()) })
+	mismatch(func() { p0.GT().PointGT().Pairing(b, p1.G2().Point().Base()) })
+}
+
+func TestPairingProduct(t *testing.T) {
+	p := NewPairingFp254BNb()
+	var p1s, p2s []abstract.Point
+	exp := p.GT().Point().Null()
+	for i := 0; i < 4; i++ {
+		p1 := p.G1().Point().Mul(nil, p.G1().Scalar().Pick(random.Stream))
+		p2 := p.G2().Point().Mul(nil, p.G2().Scalar().Pick(random.Stream))
+		p1s = append(p1s, p1)
+		p2s = append(p2s, p2)
+		exp.Add(exp, p.GT().PointGT().Pairing(p1, p2))
+	}
+	require.True(t, p.GT().PointGT().PairingProduct(p1s, p2s).Equal(exp))
+	require.True(t, p.GT().PointGT().PairingProduct(nil, nil).Equal(p.GT().Point().Null()))
+
+	// e(a * g1, g2) * e(g1, -a * g2) == 1
+	a := p.G1().Scalar().Pick(random.Stream)
+	p1s = []abstract.Point{p.G1().Point().Mul(nil, a), p.G1().Point().Base()}
+	p2s = []abstract.Point{p.G2().Point().Base(), p.G2().Point().Neg(p.G2().Point().Mul(nil, a))}
+	require.True(t, p.GT().PointGT().PairingProduct(p1s, p2s).Equal(p.GT().Point().Null()))
+
+	require.Panics(t, func() { p.GT().PointGT().PairingProduct(p1s, p2s[:1]) })
+}
+
+func BenchmarkPairing(b *testing.B) {
+	p := NewPairingFp254BNb()
+	p1 := p.G1().Point().Base()
+	p2 := p.G2().Point().Base()
+	e := p.GT().PointGT()
+	for i := 0; i < b.N; i++ {
+		e.Pairing(p1, p2)
+	}
+}
+
+func BenchmarkPairingProduct2(b *testing.B) {
+	p := NewPairingFp254BNb()
+	p1s := []abstract.Point{p.G1().Point().Base(), p.G1().Point().Base()}
+	p2s := []abstract.Point{p.G2().Point().Base(), p.G2().Point().Base()}
+	e := p.GT().PointGT()
+	for i := 0; i < b.N; i++ {
+		e.PairingProduct(p1s, p2s)
+	}
 }
@@ -0,0 +1,510 @@
+package pbc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// PointHasher is implemented by the groups G1 and G2. HashToPoint hashes msg
+// to a point of the group following the hash_to_curve construction of RFC
+// 9380: expand_message_xmd with SHA-256 and the domain separation tag dst,
+// hash_to_field to two field elements, a map to the curve for each of them
+// and cofactor clearing of their sum. The map is the Shallue-van de Woestijne
+// one on Barreto-Naehrig curves, and the simplified SWU one through an
+// isogeny on BLS12-381, which makes HashToPoint the suites
+// BLS12381G1_XMD:SHA-256_SSWU_RO_ and BLS12381G2_XMD:SHA-256_SSWU_RO_ there.
+// Different tags give independent hash functions; dst must not be empty.
+//
+// The computation is not constant time.
+type PointHasher interface {
+	HashToPoint(msg, dst []byte) abstract.Point
+}
+
+// h2cSecurity is the security parameter k of hash_to_field, in bits.
+const h2cSecurity = 128
+
+var errEmptyDST = errors.New("pbc: empty domain separation tag")
+
+func (g *g1group) HashToPoint(msg, dst []byte) abstract.Point {
+	params := h2cParamsFor(g.curve)
+	u := hashToField(msg, dst, 2, params.f1)
+	q0 := params.map1.mapToCurve(u[0])
+	q1 := params.map1.mapToCurve(u[1])
+
+	p := newPointG1(g.base, g.format)
+	defer withCurve(g.curve)()
+	a, b := newG1(g.curve), newG1(g.curve)
+	if err := setPointString(&a, q0); err != nil {
+		panic(err)
+	}
+	if err := setPointString(&b, q1); err != nil {
+		panic(err)
+	}
+	g1Add(&a, &a, &b)
+	if params.h1 == nil {
+		// G1 of Barreto-Naehrig curves has no cofactor
+		p.g = a
+		return p
+	}
+	g1MulBig(&p.g, &a, params.h1)
+	return p
+}
+
+func (g *g2group) HashToPoint(msg, dst []byte) abstract.Point {
+	params := h2cParamsFor(g.curve)
+	u := hashToField(msg, dst, 2, params.f2)
+	q0 := params.map2.mapToCurve(u[0])
+	q1 := params.map2.mapToCurve(u[1])
+
+	p := newPointG2(g.base, g.format)
+	defer withCurve(g.curve)()
+	a, b := newG2(g.curve), newG2(g.curve)
+	if err := setPointString(&a, q0); err != nil {
+		panic(err)
+	}
+	if err := setPointString(&b, q1); err != nil {
+		panic(err)
+	}
+	g2Add(&a, &a, &b)
+	g2MulBig(&p.g, &a, params.h2)
+	return p
+}
+
+// setPointString sets p to the affine point (x, y) through the string format
+// of the backend. It must be called while holding the curve.
+func setPointString(p stringer, xy [2]fext) error {
+	parts := []string{"1"}
+	for _, c := range xy {
+		for _, e := range c {
+			parts = append(parts, e.Text(16))
+		}
+	}
+	return p.SetString(strings.Join(parts, " "), 16)
+}
+
+// g1MulBig sets z = k * x with additions only, see g1InSubgroup.
+func g1MulBig(z, x *g1, k *big.Int) {
+	acc := *x
+	acc.Clear()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		g1Add(&acc, &acc, &acc)
+		if k.Bit(i) == 1 {
+			g1Add(&acc, &acc, x)
+		}
+	}
+	*z = acc
+}
+
+// g2MulBig is the G2 version of g1MulBig.
+func g2MulBig(z, x *g2, k *big.Int) {
+	acc := *x
+	acc.Clear()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		g2Add(&acc, &acc, &acc)
+		if k.Bit(i) == 1 {
+			g2Add(&acc, &acc, x)
+		}
+	}
+	*z = acc
+}
+
+// expandMessageXMD is expand_message_xmd of RFC 9380 with SHA-256.
+func expandMessageXMD(msg, dst []byte, n int) []byte {
+	if len(dst) == 0 {
+		panic(errEmptyDST)
+	}
+	if len(dst) > 255 {
+		h := sha256.New()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	const bsize = sha256.Size
+	ell := (n + bsize - 1) / bsize
+	if ell > 255 || n > 65535 {
+		panic("pbc: expand_message_xmd output too long")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize)) // Z_pad
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+	out := append([]byte{}, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, bsize)
+		for j := range x {
+			x[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:n]
+}
+
+// hashToField is hash_to_field of RFC 9380, returning count elements of f.
+func hashToField(msg, dst []byte, count int, f *extField) []fext {
+	L := (f.p.BitLen() + h2cSecurity + 7) / 8
+	buff := expandMessageXMD(msg, dst, count*f.m*L)
+	u := make([]fext, count)
+	for i := range u {
+		u[i] = f.zero()
+		for j := 0; j < f.m; j++ {
+			off := L * (j + i*f.m)
+			u[i][j].SetBytes(buff[off : off+L])
+			u[i][j].Mod(u[i][j], f.p)
+		}
+	}
+	return u
+}
+
+// fext is an element of Fp (one coordinate) or Fp2 = Fp[i] / (i^2 + 1) (two
+// coordinates), the fields over which mcl defines G1 and G2.
+type fext []*big.Int
+
+// extField implements the arithmetic of Fp when m = 1 and Fp2 when m = 2. It
+// requires p = 3 mod 4.
+type extField struct {
+	p *big.Int
+	m int
+}
+
+func (f *extField) zero() fext {
+	e := make(fext, f.m)
+	for i := range e {
+		e[i] = new(big.Int)
+	}
+	return e
+}
+
+func (f *extField) fromInt(v int64) fext {
+	e := f.zero()
+	e[0].Mod(big.NewInt(v), f.p)
+	return e
+}
+
+func (f *extField) add(a, b fext) fext {
+	c := f.zero()
+	for i := range c {
+		c[i].Add(a[i], b[i])
+		c[i].Mod(c[i], f.p)
+	}
+	return c
+}
+
+func (f *extField) sub(a, b fext) fext {
+	c := f.zero()
+	for i := range c {
+		c[i].Sub(a[i], b[i])
+		c[i].Mod(c[i], f.p)
+	}
+	return c
+}
+
+func (f *extField) neg(a fext) fext {
+	return f.sub(f.zero(), a)
+}
+
+func (f *extField) mul(a, b fext) fext {
+	c := f.zero()
+	f.mulTo(c, a, b, new(big.Int))
+	return c
+}
+
+// mulTo sets c = a * b with t as scratch space. c must not alias a or b.
+func (f *extField) mulTo(c, a, b fext, t *big.Int) {
+	if f.m == 1 {
+		c[0].Mul(a[0], b[0])
+		c[0].Mod(c[0], f.p)
+		return
+	}
+	// (a0 + a1 i)(b0 + b1 i) = a0 b0 - a1 b1 + (a0 b1 + a1 b0) i
+	c[0].Mul(a[0], b[0])
+	c[0].Sub(c[0], t.Mul(a[1], b[1]))
+	c[0].Mod(c[0], f.p)
+	c[1].Mul(a[0], b[1])
+	c[1].Add(c[1], t.Mul(a[1], b[0]))
+	c[1].Mod(c[1], f.p)
+}
+
+// exp returns a^k. The square and multiply loop alternates between two
+// preallocated elements, since it runs for every square root taken by the
+// map to the curve.
+func (f *extField) exp(a fext, k *big.Int) fext {
+	if f.m == 1 {
+		return fext{new(big.Int).Exp(a[0], k, f.p)}
+	}
+	r, s := f.fromInt(1), f.zero()
+	t := new(big.Int)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		f.mulTo(s, r, r, t)
+		r, s = s, r
+		if k.Bit(i) == 1 {
+			f.mulTo(s, r, a, t)
+			r, s = s, r
+		}
+	}
+	return r
+}
+
+// norm returns a0^2 + a1^2 for Fp2 and a for Fp, as an element of Fp.
+func (f *extField) norm(a fext) *big.Int {
+	if f.m == 1 {
+		return new(big.Int).Mod(a[0], f.p)
+	}
+	n := new(big.Int)
+	for _, c := range a {
+		n.Add(n, new(big.Int).Mul(c, c))
+	}
+	return n.Mod(n, f.p)
+}
+
+// inv0 returns 1/a, or 0 if a = 0.
+func (f *extField) inv0(a fext) fext {
+	c := f.zero()
+	n := f.norm(a)
+	if n.Sign() == 0 {
+		return c
+	}
+	n.ModInverse(n, f.p)
+	if f.m == 1 {
+		c[0] = n
+		return c
+	}
+	// 1 / (a0 + a1 i) = (a0 - a1 i) / (a0^2 + a1^2)
+	c[0].Mul(a[0], n)
+	c[0].Mod(c[0], f.p)
+	c[1].Mul(a[1], n)
+	c[1].Neg(c[1])
+	c[1].Mod(c[1], f.p)
+	return c
+}
+
+func (f *extField) isZero(a fext) bool {
+	for _, c := range a {
+		if c.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *extField) equal(a, b fext) bool {
+	return f.isZero(f.sub(a, b))
+}
+
+// isSquare returns true if a is a square, which is the case when its norm is
+// a square in Fp.
+func (f *extField) isSquare(a fext) bool {
+	return big.Jacobi(f.norm(a), f.p) >= 0
+}
+
+// sqrt returns a square root of a, which must be a square.
+func (f *extField) sqrt(a fext) fext {
+	if f.m == 1 {
+		e := new(big.Int).Add(f.p, big.NewInt(1))
+		e.Rsh(e, 2)
+		return f.exp(a, e)
+	}
+	// Algorithm 9 of "Square root computation over even extension fields"
+	e := new(big.Int).Sub(f.p, big.NewInt(3))
+	e.Rsh(e, 2)
+	a1 := f.exp(a, e)
+	alpha := f.mul(a1, f.mul(a1, a))
+	x0 := f.mul(a1, a)
+	minusOne := f.fromInt(-1)
+	if f.equal(alpha, minusOne) {
+		// i * x0
+		return fext{new(big.Int).Mod(new(big.Int).Neg(x0[1]), f.p), x0[0]}
+	}
+	e.Sub(f.p, big.NewInt(1))
+	e.Rsh(e, 1)
+	b := f.exp(f.add(alpha, f.fromInt(1)), e)
+	return f.mul(b, x0)
+}
+
+// sgn0 is the sign of a as defined by RFC 9380.
+func (f *extField) sgn0(a fext) uint {
+	sign := a[0].Bit(0)
+	if f.m == 2 && a[0].Sign() == 0 {
+		sign |= a[1].Bit(0)
+	}
+	return sign
+}
+
+// svdw holds the constants of the Shallue-van de Woestijne map to the curve
+// y^2 = x^3 + b, section 6.6.1 of RFC 9380.
+type svdw struct {
+	f              *extField
+	b              fext
+	z              fext
+	c1, c2, c3, c4 fext
+}
+
+func (f *extField) curveEq(b, x fext) fext {
+	return f.add(f.mul(f.mul(x, x), x), b)
+}
+
+// newSVDW finds the constant Z the way the reference find_z_svdw does and
+// computes the constants of the map.
+func newSVDW(f *extField, b fext) *svdw {
+	s := &svdw{f: f, b: b}
+	three, four := f.fromInt(3), f.fromInt(4)
+	for ctr := int64(1); s.z == nil; ctr++ {
+		for _, z := range []fext{f.fromInt(ctr), f.fromInt(-ctr)} {
+			gz := f.curveEq(b, z)
+			if f.isZero(gz) {
+				continue
+			}
+			// h(Z) = -3 Z^2 / (4 g(Z))
+			hz := f.mul(f.neg(f.mul(three, f.mul(z, z))), f.inv0(f.mul(four, gz)))
+			if f.isZero(hz) || !f.isSquare(hz) {
+				continue
+			}
+			mz2 := f.mul(f.neg(z), f.inv0(f.fromInt(2)))
+			if f.isSquare(gz) || f.isSquare(f.curveEq(b, mz2)) {
+				s.z = z
+				break
+			}
+		}
+	}
+	z := s.z
+	s.c1 = f.curveEq(b, z)
+	s.c2 = f.mul(f.neg(z), f.inv0(f.fromInt(2)))
+	threeZ2 := f.mul(three, f.mul(z, z))
+	s.c3 = f.sqrt(f.neg(f.mul(s.c1, threeZ2)))
+	if f.sgn0(s.c3) == 1 {
+		s.c3 = f.neg(s.c3)
+	}
+	s.c4 = f.mul(f.neg(f.mul(four, s.c1)), f.inv0(threeZ2))
+	return s
+}
+
+// mapToCurve is map_to_curve_svdw of RFC 9380.
+func (s *svdw) mapToCurve(u fext) [2]fext {
+	f := s.f
+	one := f.fromInt(1)
+	tv1 := f.mul(f.mul(u, u), s.c1)
+	tv2 := f.add(one, tv1)
+	tv1 = f.sub(one, tv1)
+	tv3 := f.inv0(f.mul(tv1, tv2))
+	tv4 := f.mul(f.mul(f.mul(u, tv1), tv3), s.c3)
+	x1 := f.sub(s.c2, tv4)
+	x2 := f.add(s.c2, tv4)
+	x3 := f.mul(tv2, tv2)
+	x3 = f.mul(x3, tv3)
+	x3 = f.mul(x3, x3)
+	x3 = f.add(f.mul(x3, s.c4), s.z)
+
+	var x fext
+	switch {
+	case f.isSquare(f.curveEq(s.b, x1)):
+		x = x1
+	case f.isSquare(f.curveEq(s.b, x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y := f.sqrt(f.curveEq(s.b, x))
+	if f.sgn0(u) != f.sgn0(y) {
+		y = f.neg(y)
+	}
+	return [2]fext{x, y}
+}
+
+// curveMap is a map from a field element to an affine point of a curve,
+// implemented by svdw and sswu.
+type curveMap interface {
+	mapToCurve(u fext) [2]fext
+}
+
+// h2cParams are the parameters of the hash to curve of a curve, derived from
+// its generators so they do not depend on the backend.
+type h2cParams struct {
+	f1, f2     *extField
+	map1, map2 curveMap
+	// h1 and h2 clear the cofactors of G1 and G2. h1 is nil when G1 has no
+	// cofactor.
+	h1, h2 *big.Int
+}
+
+var h2cCache = struct {
+	sync.Mutex
+	m map[int]*h2cParams
+}{m: make(map[int]*h2cParams)}
+
+// h2cParamsFor returns the hash to curve parameters of the curve. It must not
+// be called while holding the curve.
+func h2cParamsFor(curve int) *h2cParams {
+	h2cCache.Lock()
+	defer h2cCache.Unlock()
+	if params, ok := h2cCache.m[curve]; ok {
+		return params
+	}
+
+	r := curveOrder(curve)
+	p := curveModulus(curve)
+	if p.Bit(0) != 1 || p.Bit(1) != 1 {
+		panic(fmt.Sprintf("pbc: hash to curve needs p = 3 mod 4 on %s", curveName(curve)))
+	}
+
+	f1 := &extField{p: p, m: 1}
+	f2 := &extField{p: p, m: 2}
+	// b = y^2 - x^3 from the generators
+	xy := parsePointString(generator(curve, 0))
+	x1, y1 := fext{xy[0]}, fext{xy[1]}
+	b1 := f1.sub(f1.mul(y1, y1), f1.mul(f1.mul(x1, x1), x1))
+	xy2 := parsePointString(generator(curve, 1))
+	x2, y2 := fext{xy2[0], xy2[1]}, fext{xy2[2], xy2[3]}
+	b2 := f2.sub(f2.mul(y2, y2), f2.mul(f2.mul(x2, x2), x2))
+
+	params := &h2cParams{f1: f1, f2: f2}
+	if curve == CurveBLS12_381 {
+		params.map1, params.map2 = newSSWUBLS12381(f1, f2)
+		params.h1, _ = new(big.Int).SetString(bls12381HEffG1, 16)
+		params.h2, _ = new(big.Int).SetString(bls12381HEffG2, 16)
+	} else {
+		params.map1 = newSVDW(f1, b1)
+		params.map2 = newSVDW(f2, b2)
+		// the cofactor 2p - r of G2
+		params.h2 = new(big.Int).Sub(new(big.Int).Lsh(p, 1), r)
+	}
+	h2cCache.m[curve] = params
+	return params
+}
+
+// parsePointString returns the coordinates of a point given in the "1 x y"
+// format with hexadecimal numbers.
+func parsePointString(s string) []*big.Int {
+	parts := strings.Fields(s)
+	if len(parts) < 3 || parts[0] != "1" {
+		panic("pbc: invalid point string " + s)
+	}
+	var coords []*big.Int
+	for _, c := range parts[1:] {
+		b, ok := new(big.Int).SetString(c, 16)
+		if !ok {
+			panic("pbc: invalid point string " + s)
+		}
+		coords = append(coords, b)
+	}
+	return coords
+}
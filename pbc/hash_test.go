@@ -0,0 +1,81 @@
+package pbc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// RFC 9380, appendix K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg, out string
+	}{
+		{"", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+	}
+	for _, v := range vectors {
+		out := expandMessageXMD([]byte(v.msg), dst, 32)
+		require.Equal(t, v.out, hex.EncodeToString(out))
+	}
+	require.Len(t, expandMessageXMD(nil, dst, 200), 200)
+	require.Panics(t, func() { expandMessageXMD(nil, nil, 32) })
+}
+
+func TestFieldSqrt(t *testing.T) {
+	params := h2cParamsFor(CurveFp254BNb)
+	for _, f := range []*extField{params.f1, params.f2} {
+		for i := 0; i < 20; i++ {
+			a := f.zero()
+			for j := range a {
+				a[j].SetBytes(random.Bytes(40, random.Stream))
+				a[j].Mod(a[j], f.p)
+			}
+			s := f.mul(a, a)
+			require.True(t, f.isSquare(s))
+			r := f.sqrt(s)
+			require.True(t, f.equal(f.mul(r, r), s))
+			require.True(t, f.equal(f.mul(a, f.inv0(a)), f.fromInt(1)))
+		}
+	}
+	require.Equal(t, 0, big.NewInt(3).Cmp(new(big.Int).Mod(params.f1.p, big.NewInt(4))))
+}
+
+func TestHashToPoint(t *testing.T) {
+	var groups []abstract.Group
+	for _, curve := range []int{CurveFp254BNb, CurveBLS12_381} {
+		if Supported(curve) {
+			p := NewPairing(curve)
+			groups = append(groups, p.G1(), p.G2())
+		}
+	}
+	dst := []byte("PBC-TEST-DST")
+	for _, g := range groups {
+		h := g.(PointHasher)
+		p1 := h.HashToPoint([]byte("hello"), dst)
+		require.True(t, p1.Equal(h.HashToPoint([]byte("hello"), dst)))
+		require.False(t, p1.Equal(h.HashToPoint([]byte("hellO"), dst)))
+		require.False(t, p1.Equal(h.HashToPoint([]byte("hello"), []byte("PBC-TEST-DST2"))))
+		require.False(t, p1.Equal(g.Point().Null()))
+
+		// the decoding checks the point is in the subgroup
+		buff, err := p1.MarshalBinary()
+		require.Nil(t, err)
+		require.Nil(t, g.Point().UnmarshalBinary(buff))
+
+		require.Panics(t, func() { h.HashToPoint([]byte("hello"), nil) })
+	}
+}
+
+func BenchmarkG1HashToPoint(b *testing.B) {
+	h := NewPairingFp254BNb().G1().(PointHasher)
+	for i := 0; i < b.N; i++ {
+		h.HashToPoint([]byte("hello"), []byte("PBC-BENCH"))
+	}
+}
@@ -0,0 +1,269 @@
+package pbc
+
+import (
+	"unsafe"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// MultiMuler is implemented by the points of G1 and G2. MultiMul sets the
+// receiver to the sum of scalars[i] * points[i] using Pippenger's bucket
+// method, which is much cheaper than one Mul per term as soon as there are a
+// few terms. If the receiver or one of the operands is in constant time mode,
+// see VarTimer, it falls back to one ladder per term.
+type MultiMuler interface {
+	MultiMul(points []abstract.Point, scalars []abstract.Scalar) abstract.Point
+}
+
+// MultiMul returns the sum of scalars[i] * points[i] as a new point of g. It
+// uses the multi-scalar multiplication of the group when there is one and
+// falls back to Mul and Add otherwise, so it can be used with any suite.
+func MultiMul(g abstract.Group, points []abstract.Point, scalars []abstract.Scalar) abstract.Point {
+	if len(points) != len(scalars) {
+		panic("pbc: multi-scalar multiplication of slices of different lengths")
+	}
+	p := g.Point()
+	if m, ok := p.(MultiMuler); ok {
+		return m.MultiMul(points, scalars)
+	}
+	p.Null()
+	t := g.Point()
+	for i := range points {
+		p.Add(p, t.Mul(points[i], scalars[i]))
+	}
+	return p
+}
+
+// EvalPubPoly returns the share of the public polynomial p at index i, like
+// p.Eval(i), with a single multi-scalar multiplication of the commitments by
+// the powers of i + 1.
+func EvalPubPoly(g abstract.Group, p *share.PubPoly, i int) *share.PubShare {
+	_, commits := p.Info()
+	xi := g.Scalar().SetInt64(1 + int64(i))
+	powers := newScalars(g, len(commits))
+	for j := range powers {
+		if j == 0 {
+			powers[j].One()
+			continue
+		}
+		powers[j].Mul(powers[j-1], xi)
+	}
+	return &share.PubShare{I: i, V: MultiMul(g, commits, powers)}
+}
+
+// newScalars returns n new scalars of g. The scalars of this package are
+// allocated at once.
+func newScalars(g abstract.Group, n int) []abstract.Scalar {
+	res := make([]abstract.Scalar, n)
+	if s, ok := g.Scalar().(*scalar); ok {
+		backing := make([]scalar, n)
+		for i := range res {
+			backing[i] = scalar{fe: newFr(s.curve), curve: s.curve}
+			res[i] = &backing[i]
+		}
+		return res
+	}
+	for i := range res {
+		res[i] = g.Scalar()
+	}
+	return res
+}
+
+func (p *pointG1) MultiMul(points []abstract.Point, scalars []abstract.Scalar) abstract.Point {
+	if len(points) != len(scalars) {
+		panic("pbc: multi-scalar multiplication of slices of different lengths")
+	}
+	ps := make([]*g1, len(points))
+	for i, pt := range points {
+		pg := pt.(*pointG1)
+		checkCurve(p.curve, pg.curve, scalars[i].(*scalar).curve)
+		ps[i] = &pg.g
+	}
+	if p.constTime || msmConstTime(points, scalars) {
+		r := curveOrder(p.curve)
+		defer withCurve(p.curve)()
+		acc, t := newG1(p.curve), newG1(p.curve)
+		acc.Clear()
+		for i := range ps {
+			g1MulCT(&t, ps[i], &scalars[i].(*scalar).fe, r)
+			g1Add(&acc, &acc, &t)
+		}
+		zeroize(unsafe.Pointer(&t), unsafe.Sizeof(t))
+		p.g = acc
+		return p
+	}
+	defer withCurve(p.curve)()
+	acc, sum, wsum := newG1(p.curve), newG1(p.curve), newG1(p.curve)
+	acc.Clear()
+	if len(ps) < msmMin {
+		for i := range ps {
+			g1Mul(&sum, ps[i], &scalars[i].(*scalar).fe)
+			g1Add(&acc, &acc, &sum)
+		}
+		p.g = acc
+		return p
+	}
+
+	digits, c := msmDigits(scalars)
+	buckets := make([]g1, 1<<c-1)
+	for i := range buckets {
+		buckets[i] = newG1(p.curve)
+	}
+	for w := len(digits[0]) - 1; w >= 0; w-- {
+		for i := uint(0); i < c; i++ {
+			g1Add(&acc, &acc, &acc)
+		}
+		for i := range buckets {
+			buckets[i].Clear()
+		}
+		for i, d := range digits {
+			if d[w] != 0 {
+				g1Add(&buckets[d[w]-1], &buckets[d[w]-1], ps[i])
+			}
+		}
+		// sum of j * buckets[j-1] with running sums
+		sum.Clear()
+		wsum.Clear()
+		for i := len(buckets) - 1; i >= 0; i-- {
+			g1Add(&sum, &sum, &buckets[i])
+			g1Add(&wsum, &wsum, &sum)
+		}
+		g1Add(&acc, &acc, &wsum)
+	}
+	p.g = acc
+	return p
+}
+
+func (p *pointG2) MultiMul(points []abstract.Point, scalars []abstract.Scalar) abstract.Point {
+	if len(points) != len(scalars) {
+		panic("pbc: multi-scalar multiplication of slices of different lengths")
+	}
+	ps := make([]*g2, len(points))
+	for i, pt := range points {
+		pg := pt.(*pointG2)
+		checkCurve(p.curve, pg.curve, scalars[i].(*scalar).curve)
+		ps[i] = &pg.g
+	}
+	if p.constTime || msmConstTime(points, scalars) {
+		r := curveOrder(p.curve)
+		defer withCurve(p.curve)()
+		acc, t := newG2(p.curve), newG2(p.curve)
+		acc.Clear()
+		for i := range ps {
+			g2MulCT(&t, ps[i], &scalars[i].(*scalar).fe, r)
+			g2Add(&acc, &acc, &t)
+		}
+		zeroize(unsafe.Pointer(&t), unsafe.Sizeof(t))
+		p.g = acc
+		return p
+	}
+	defer withCurve(p.curve)()
+	acc, sum, wsum := newG2(p.curve), newG2(p.curve), newG2(p.curve)
+	acc.Clear()
+	if len(ps) < msmMin {
+		for i := range ps {
+			g2Mul(&sum, ps[i], &scalars[i].(*scalar).fe)
+			g2Add(&acc, &acc, &sum)
+		}
+		p.g = acc
+		return p
+	}
+
+	digits, c := msmDigits(scalars)
+	buckets := make([]g2, 1<<c-1)
+	for i := range buckets {
+		buckets[i] = newG2(p.curve)
+	}
+	for w := len(digits[0]) - 1; w >= 0; w-- {
+		for i := uint(0); i < c; i++ {
+			g2Add(&acc, &acc, &acc)
+		}
+		for i := range buckets {
+			buckets[i].Clear()
+		}
+		for i, d := range digits {
+			if d[w] != 0 {
+				g2Add(&buckets[d[w]-1], &buckets[d[w]-1], ps[i])
+			}
+		}
+		sum.Clear()
+		wsum.Clear()
+		for i := len(buckets) - 1; i >= 0; i-- {
+			g2Add(&sum, &sum, &buckets[i])
+			g2Add(&wsum, &wsum, &sum)
+		}
+		g2Add(&acc, &acc, &wsum)
+	}
+	p.g = acc
+	return p
+}
+
+// msmConstTime returns true if one of the points or scalars is in constant
+// time mode. MultiMul then runs the ladder of Mul for each term instead of the
+// bucket method, whose memory accesses depend on the digits of the scalars.
+func msmConstTime(points []abstract.Point, scalars []abstract.Scalar) bool {
+	for i := range points {
+		if scalars[i].(*scalar).constTime {
+			return true
+		}
+		switch p := points[i].(type) {
+		case *pointG1:
+			if p.constTime {
+				return true
+			}
+		case *pointG2:
+			if p.constTime {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// msmMin is the number of terms from which the bucket method is faster than
+// multiplying each point separately.
+const msmMin = 4
+
+// msmWindow returns the width of the windows for n terms, roughly log2(n)
+// which balances the additions into the buckets against the additions of the
+// buckets themselves.
+func msmWindow(n int) uint {
+	c := uint(2)
+	for n >>= 2; n > 1 && c < 16; n >>= 1 {
+		c++
+	}
+	return c
+}
+
+// msmDigits splits the non-empty list of scalars in windows of c bits, least
+// significant first. It must be called while holding the curve.
+func msmDigits(scalars []abstract.Scalar) ([][]uint32, uint) {
+	c := msmWindow(len(scalars))
+	digits := make([][]uint32, len(scalars))
+	var backing []uint32
+	for i, s := range scalars {
+		buff := s.(*scalar).fe.Serialize()
+		nbits := uint(len(buff) * 8)
+		nd := int((nbits + c - 1) / c)
+		if backing == nil {
+			backing = make([]uint32, nd*len(scalars))
+		}
+		d := backing[i*nd : (i+1)*nd : (i+1)*nd]
+		for j := range d {
+			d[j] = window(buff, uint(j)*c, c)
+		}
+		digits[i] = d
+	}
+	return digits, c
+}
+
+// window returns the c bits of the little-endian buff starting at bit.
+func window(buff []byte, bit, c uint) uint32 {
+	var w uint32
+	for i := uint(0); i < c && bit+i < uint(len(buff)*8); i++ {
+		b := bit + i
+		w |= uint32(buff[b/8]>>(b%8)&1) << i
+	}
+	return w
+}
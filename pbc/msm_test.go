@@ -0,0 +1,104 @@
+package pbc
+
+import (
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomTerms(g abstract.Group, n int) ([]abstract.Point, []abstract.Scalar) {
+	points := make([]abstract.Point, n)
+	scalars := make([]abstract.Scalar, n)
+	for i := range points {
+		points[i] = g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+		scalars[i] = g.Scalar().Pick(random.Stream)
+	}
+	return points, scalars
+}
+
+func TestMultiMul(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Group{p.G1(), p.G2()} {
+		for _, n := range []int{0, 1, 3, 4, 5, 17, 70} {
+			points, scalars := randomTerms(g, n)
+			if n > 2 {
+				points[1].Null()
+				scalars[2].Zero()
+				scalars[0].SetInt64(-1)
+			}
+			exp := g.Point().Null()
+			for i := range points {
+				exp.Add(exp, g.Point().Mul(points[i], scalars[i]))
+			}
+			require.True(t, MultiMul(g, points, scalars).Equal(exp), "%s: %d terms", g, n)
+		}
+	}
+
+	// the fallback gives the same results
+	g := p.GT()
+	points := []abstract.Point{g.Point().Base(), g.Point().Base()}
+	scalars := []abstract.Scalar{g.Scalar().SetInt64(2), g.Scalar().SetInt64(3)}
+	exp := g.Point().Mul(nil, g.Scalar().SetInt64(5))
+	require.True(t, MultiMul(g, points, scalars).Equal(exp))
+
+	require.Panics(t, func() { MultiMul(g, points, scalars[:1]) })
+}
+
+func TestEvalPubPoly(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveBLS12_381} {
+		if Supported(curve) {
+			testEvalPubPoly(t, NewPairing(curve))
+		}
+	}
+}
+
+func testEvalPubPoly(t *testing.T, p *Pairing) {
+	g := p.G2()
+	n, th := 10, 6
+	secret := g.Scalar().Pick(random.Stream)
+	priPoly := share.NewPriPoly(g, th, secret, random.Stream)
+	pubPoly := priPoly.Commit(g.Point().Base())
+	pubShares := make([]*share.PubShare, n)
+	for i := 0; i < n; i++ {
+		pubShares[i] = EvalPubPoly(g, pubPoly, i)
+		require.Equal(t, i, pubShares[i].I)
+		require.True(t, pubShares[i].V.Equal(pubPoly.Eval(i).V))
+	}
+
+}
+
+func BenchmarkG1MultiMul64(b *testing.B) {
+	g := NewPairingFp254BNb().G1()
+	points, scalars := randomTerms(g, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MultiMul(g, points, scalars)
+	}
+}
+
+func BenchmarkG1MulAdd64(b *testing.B) {
+	g := NewPairingFp254BNb().G1()
+	points, scalars := randomTerms(g, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acc, t := g.Point().Null(), g.Point()
+		for j := range points {
+			acc.Add(acc, t.Mul(points[j], scalars[j]))
+		}
+	}
+}
+
+func BenchmarkEvalPubPoly(b *testing.B) {
+	g := NewPairingFp254BNb().G2()
+	priPoly := share.NewPriPoly(g, 64, g.Scalar().Pick(random.Stream), random.Stream)
+	pubPoly := priPoly.Commit(g.Point().Base())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Release(g, EvalPubPoly(g, pubPoly, i%100).V)
+	}
+}
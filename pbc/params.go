@@ -0,0 +1,122 @@
+package pbc
+
+import (
+	"math/big"
+	"sync"
+)
+
+// CurveParams describes the curve of a Pairing. All the curves implemented so
+// far are curves y^2 = x^3 + b over Fp with G2 defined on a sextic twist over
+// Fp2: Barreto-Naehrig curves, and the Barreto-Lynn-Scott curve BLS12-381.
+type CurveParams struct {
+	// ID is the identifier of the curve given to NewPairing.
+	ID int
+	// Name is the canonical name of the curve, understood by Curve.
+	Name string
+	// Order is the prime order r of G1, G2 and GT, i.e. the modulus of the
+	// scalars.
+	Order *big.Int
+	// Modulus is the characteristic p of the base field.
+	Modulus *big.Int
+	// EmbeddingDegree is the degree k of the extension of Fp in which GT is
+	// the subgroup of order r.
+	EmbeddingDegree int
+	// CofactorG1 and CofactorG2 are the indexes of G1 and G2 in the groups
+	// of points of the curve and of its twist.
+	CofactorG1, CofactorG2 *big.Int
+	// SecurityBits is an estimate of the security level of the pairing, taking
+	// into account the number field sieve variants for discrete logarithms in
+	// GT which put BN and BLS curves below the level of their order.
+	SecurityBits int
+	// ScalarLen is the size in bytes of the encoding of a scalar.
+	ScalarLen int
+}
+
+// Params returns the parameters of the curve of the pairing. The returned
+// structure can be modified freely.
+func (p *Pairing) Params() *CurveParams {
+	r := curveOrder(p.curve)
+	q := curveModulus(p.curve)
+	params := &CurveParams{
+		ID:              p.curve,
+		Name:            curveName(p.curve),
+		Order:           new(big.Int).Set(r),
+		Modulus:         new(big.Int).Set(q),
+		EmbeddingDegree: 12,
+		CofactorG1:      big.NewInt(1),
+		// #E'(Fp2) = r (2p - r) for BN curves
+		CofactorG2:   new(big.Int).Sub(new(big.Int).Lsh(q, 1), r),
+		SecurityBits: securityBits(p.curve),
+		ScalarLen:    scalarSize(p.curve),
+	}
+	if p.curve == CurveBLS12_381 {
+		params.CofactorG1, params.CofactorG2 = bls12Cofactors(bls12381X)
+	}
+	return params
+}
+
+// bls12381X is the parameter x of BLS12-381, -0xd201000000010000.
+var bls12381X, _ = new(big.Int).SetString("-d201000000010000", 16)
+
+// bls12Cofactors returns the cofactors of G1 and G2 of the BLS12 curve of
+// parameter x: (x - 1)^2 / 3 and
+// (x^8 - 4x^7 + 5x^6 - 4x^4 + 6x^3 - 4x^2 - 4x + 13) / 9.
+func bls12Cofactors(x *big.Int) (*big.Int, *big.Int) {
+	h1 := new(big.Int).Sub(x, big.NewInt(1))
+	h1.Mul(h1, h1)
+	h1.Quo(h1, big.NewInt(3))
+
+	h2 := new(big.Int)
+	for _, c := range []int64{1, -4, 5, 0, -4, 6, -4, -4, 13} {
+		h2.Mul(h2, x)
+		h2.Add(h2, big.NewInt(c))
+	}
+	h2.Quo(h2, big.NewInt(9))
+	return h1, h2
+}
+
+// securityBits returns the estimated security of the curve following
+// Barbulescu and Duquesne, "Updating key size estimations for pairings".
+func securityBits(curve int) int {
+	switch curve {
+	case CurveFp254BNb:
+		return 100
+	case CurveFp382_1, CurveFp382_2:
+		return 110
+	case CurveBLS12_381:
+		// below the 128 bits first claimed for it
+		return 117
+	default:
+		panic("pairing curve unknown")
+	}
+}
+
+var moduli = struct {
+	sync.Mutex
+	m map[int]*big.Int
+}{m: make(map[int]*big.Int)}
+
+// curveModulus returns the characteristic p of the base field of the curve,
+// computed as the sum of the y coordinates of the generator of G1 and of its
+// opposite. It must not be called while holding the curve.
+func curveModulus(curve int) *big.Int {
+	moduli.Lock()
+	defer moduli.Unlock()
+	if p, ok := moduli.m[curve]; ok {
+		return p
+	}
+	var negGen string
+	func() {
+		defer withCurve(curve)()
+		gen, neg := newG1(curve), newG1(curve)
+		if err := gen.SetString(generator(curve, 0), 16); err != nil {
+			panic(err)
+		}
+		g1Neg(&neg, &gen)
+		negGen = neg.GetString(16)
+	}()
+	p := parsePointString(generator(curve, 0))[1]
+	p.Add(p, parsePointString(negGen)[1])
+	moduli.m[curve] = p
+	return p
+}
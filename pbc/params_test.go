@@ -0,0 +1,59 @@
+package pbc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		p := NewPairing(curve)
+		params := p.Params()
+		require.Equal(t, curve, params.ID)
+		require.Equal(t, curve, Curve(params.Name))
+		require.True(t, params.Order.ProbablyPrime(20))
+		require.True(t, params.Modulus.ProbablyPrime(20))
+		require.Equal(t, opUnitSize(curve)*64, (params.Modulus.BitLen()+63)/64*64)
+		require.Equal(t, p.G1().ScalarLen(), params.ScalarLen)
+
+		// h1 r = p + 1 - t where the trace t is about the square root of p
+		trace := new(big.Int).Add(params.Modulus, big.NewInt(1))
+		trace.Sub(trace, new(big.Int).Mul(params.Order, params.CofactorG1))
+		require.True(t, trace.BitLen() <= params.Modulus.BitLen()/2+2)
+
+		// the points of the curve are killed by r times the cofactor of G1
+		hp := h2cParamsFor(curve)
+		xy := hp.map1.mapToCurve(hp.f1.fromInt(5))
+		func() {
+			defer withCurve(curve)()
+			q, z, zero := newG1(curve), newG1(curve), newG1(curve)
+			require.Nil(t, setPointString(&q, xy))
+			zero.Clear()
+			g1MulBig(&z, &q, new(big.Int).Mul(params.Order, params.CofactorG1))
+			require.True(t, z.IsEqual(&zero))
+		}()
+
+		// the points of the twist are killed by r times the cofactor of G2
+		xy = hp.map2.mapToCurve(hp.f2.fromInt(5))
+		func() {
+			defer withCurve(curve)()
+			q, z, zero := newG2(curve), newG2(curve), newG2(curve)
+			require.Nil(t, setPointString(&q, xy))
+			zero.Clear()
+			g2MulBig(&z, &q, params.Order)
+			require.False(t, z.IsEqual(&zero))
+			g2MulBig(&z, &q, new(big.Int).Mul(params.Order, params.CofactorG2))
+			require.True(t, z.IsEqual(&zero))
+		}()
+
+		// the returned values are copies
+		params.Order.SetInt64(0)
+		require.NotEqual(t, 0, p.Params().Order.Sign())
+	}
+	require.Equal(t, 0, NewPairingFp254BNb().Params().Modulus.Cmp(fieldModulus(t)))
+}
@@ -4,9 +4,8 @@ import (
 	"crypto/cipher"
 	"errors"
 	"io"
-	"runtime"
+	"math/big"
 
-	"github.com/dfinity/go-dfinity-crypto/bls"
 	"gopkg.in/dedis/crypto.v0/abstract"
 
 	"gopkg.in/dedis/crypto.v0/group"
@@ -14,64 +13,106 @@ import (
 )
 
 type pointG1 struct {
-	g         bls.G1
-	generator string
+	g         g1
+	curve     int
+	base      *baseG1
+	format    PointFormat
+	constTime bool
 }
 
-func newPointG1(gen string) *pointG1 {
-	pg1 := &pointG1{g: bls.G1{}, generator: gen}
-	runtime.SetFinalizer(&pg1.g, clear)
-	return pg1
+func newPointG1(base *baseG1, format PointFormat) *pointG1 {
+	return &pointG1{g: newG1(base.curve), curve: base.curve, base: base, format: format}
 }
 
 func (p *pointG1) Equal(p2 abstract.Point) bool {
 	pg := p2.(*pointG1)
+	checkCurve(p.curve, pg.curve)
+	defer withCurve(p.curve)()
 	return p.g.IsEqual(&pg.g)
 }
 
 func (p *pointG1) Null() abstract.Point {
+	defer withCurve(p.curve)()
 	p.g.Clear()
 	return p
 }
 
 func (p *pointG1) Base() abstract.Point {
-	if err := p.g.HashAndMapTo([]byte(p.generator)); err != nil {
-		panic(err)
-	}
+	p.g = *p.base.get()
 	return p
 }
 
 func (p *pointG1) Add(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG1)
 	pg2 := p2.(*pointG1)
-	bls.G1Add(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.curve, pg1.curve, pg2.curve)
+	defer withCurve(p.curve)()
+	g1Add(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointG1) Sub(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG1)
 	pg2 := p2.(*pointG1)
-	bls.G1Sub(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.curve, pg1.curve, pg2.curve)
+	defer withCurve(p.curve)()
+	g1Sub(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointG1) Neg(p1 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG1)
-	bls.G1Neg(&p.g, &pg1.g)
+	checkCurve(p.curve, pg1.curve)
+	defer withCurve(p.curve)()
+	g1Neg(&p.g, &pg1.g)
 	return p
 }
 
 func (p *pointG1) Mul(p1 abstract.Point, s abstract.Scalar) abstract.Point {
+	sc := s.(*scalar)
+	if p.constTime || sc.constTime {
+		return p.mulCT(p1, sc)
+	}
 	if p1 == nil {
-		p1 = newPointG1(p.generator).Base()
+		checkCurve(p.curve, sc.curve)
+		p.base.mul(&p.g, &sc.fe)
+		return p
 	}
-	sc := s.(*scalar)
 	pg1 := p1.(*pointG1)
-	bls.G1Mul(&p.g, &pg1.g, &sc.fe)
+	checkCurve(p.curve, pg1.curve, sc.curve)
+	defer withCurve(p.curve)()
+	g1Mul(&p.g, &pg1.g, &sc.fe)
+	return p
+}
+
+// mulCT sets p = s * p1, or s times the generator if p1 is nil, in constant
+// time.
+func (p *pointG1) mulCT(p1 abstract.Point, s *scalar) abstract.Point {
+	var x *g1
+	if p1 == nil {
+		checkCurve(p.curve, s.curve)
+		x = p.base.get()
+	} else {
+		pg1 := p1.(*pointG1)
+		checkCurve(p.curve, pg1.curve, s.curve)
+		x = &pg1.g
+	}
+	r := curveOrder(p.curve)
+	defer withCurve(p.curve)()
+	g1MulCT(&p.g, x, &s.fe, r)
 	return p
 }
 
-func (p *pointG1) MarshalBinary() (buff []byte, err error) {
+func (p *pointG1) MarshalBinary() ([]byte, error) {
+	return p.MarshalFormat(p.format)
+}
+
+// MarshalFormat implements FormatMarshaler.
+func (p *pointG1) MarshalFormat(f PointFormat) (buff []byte, err error) {
+	defer withCurve(p.curve)()
+	if f == Uncompressed {
+		return marshalUncompressed(&p.g, p.curve, 1)
+	}
 	return marshalBinary(&p.g)
 }
 
@@ -80,7 +121,43 @@ func (p *pointG1) MarshalTo(w io.Writer) (int, error) {
 }
 
 func (p *pointG1) UnmarshalBinary(buff []byte) error {
-	return p.g.Deserialize(buff)
+	_, err := p.UnmarshalFormat(buff)
+	return err
+}
+
+// UnmarshalFormat implements FormatUnmarshaler. The point is left unchanged
+// if buff is not the canonical encoding of an element of G1.
+func (p *pointG1) UnmarshalFormat(buff []byte) (PointFormat, error) {
+	f, err := formatOf(p.curve, 1, buff)
+	if err != nil {
+		return f, p.decodeError(ErrInvalidLength)
+	}
+	var r *big.Int
+	if g1HasCofactor(p.curve) {
+		r = curveOrder(p.curve)
+	}
+	defer withCurve(p.curve)()
+	q := newG1(p.curve)
+	var canonical bool
+	if f == Uncompressed {
+		canonical, err = unmarshalUncompressed(&q, p.curve, 1, buff)
+	} else {
+		canonical, err = deserialize(&q, buff)
+	}
+	switch {
+	case err != nil:
+		return f, p.decodeError(ErrNotOnCurve)
+	case !canonical:
+		return f, p.decodeError(ErrNonCanonical)
+	case r != nil && !g1InSubgroup(&q, r):
+		return f, p.decodeError(ErrNotInSubgroup)
+	}
+	p.g = q
+	return f, nil
+}
+
+func (p *pointG1) decodeError(reason error) error {
+	return &DecodeError{Group: p.name(), Reason: reason}
 }
 
 func (p *pointG1) UnmarshalFrom(r io.Reader) (int, error) {
@@ -88,10 +165,11 @@ func (p *pointG1) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (p *pointG1) MarshalSize() int {
-	return bls.GetOpUnitSize() * 8
+	return pointSize(p.curve, 1, p.format)
 }
 
 func (p *pointG1) String() string {
+	defer withCurve(p.curve)()
 	return p.g.GetString(16)
 }
 
@@ -99,89 +177,152 @@ func (p *pointG1) Pick(buff []byte, rand cipher.Stream) (abstract.Point, []byte)
 	return p.Embed(buff, rand)
 }
 
+// PickLen returns 0 on curves where G1 has a cofactor, such as BLS12-381:
+// random encodings are almost never in the subgroup, for the same reason as
+// in G2.
 func (p *pointG1) PickLen() int {
+	if g1HasCofactor(p.curve) {
+		return 0
+	}
 	// 8 bits for the randomness and 8 bits for the size of the message
-	return (bls.GetOpUnitSize() * 8) - 1 - 1
+	return pointSize(p.curve, 1, Compressed) - 1 - 1
 }
 
 func (p *pointG1) Embed(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
+	if g1HasCofactor(p.curve) {
+		s := newScalar(p.curve).Pick(rand).(*scalar)
+		p.base.mul(&p.g, &s.fe)
+		return p, data
+	}
 	res := embed(p, data, rand)
 	return p, res
 }
 
 func (p *pointG1) Data() ([]byte, error) {
-	return data(p)
+	if g1HasCofactor(p.curve) {
+		return []byte{}, nil
+	}
+	// the data is embedded in the compressed encoding
+	q := *p
+	q.format = Compressed
+	return data(&q)
 }
 
 func (p *pointG1) Clone() abstract.Point {
-	p2 := clone(p, newPointG1(p.generator))
-	return p2.(abstract.Point)
+	p2 := newPointG1(p.base, p.format)
+	p2.g = p.g
+	p2.constTime = p.constTime
+	return p2
 }
 
 func (p *pointG1) Set(p2 abstract.Point) abstract.Point {
-	clone(p2, p)
+	pg := p2.(*pointG1)
+	checkCurve(p.curve, pg.curve)
+	p.g = pg.g
 	return p
 }
 
 type pointG2 struct {
-	g         bls.G2
-	generator string
+	g         g2
+	curve     int
+	base      *baseG2
+	format    PointFormat
+	constTime bool
 }
 
-func newPointG2(gen string) *pointG2 {
-	pg := &pointG2{g: bls.G2{}, generator: gen}
-	runtime.SetFinalizer(&pg.g, clear)
-	return pg
+func newPointG2(base *baseG2, format PointFormat) *pointG2 {
+	return &pointG2{g: newG2(base.curve), curve: base.curve, base: base, format: format}
 }
 
 func (p *pointG2) Equal(p2 abstract.Point) bool {
 	pg := p2.(*pointG2)
+	checkCurve(p.curve, pg.curve)
+	defer withCurve(p.curve)()
 	return p.g.IsEqual(&pg.g)
 }
 
 func (p *pointG2) Null() abstract.Point {
+	defer withCurve(p.curve)()
 	p.g.Clear()
 	return p
 }
 
 func (p *pointG2) Base() abstract.Point {
-	if err := p.g.HashAndMapTo([]byte(p.generator)); err != nil {
-		panic(err)
-	}
+	p.g = *p.base.get()
 	return p
 }
 
 func (p *pointG2) Add(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG2)
 	pg2 := p2.(*pointG2)
-	bls.G2Add(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.curve, pg1.curve, pg2.curve)
+	defer withCurve(p.curve)()
+	g2Add(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointG2) Sub(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG2)
 	pg2 := p2.(*pointG2)
-	bls.G2Sub(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.curve, pg1.curve, pg2.curve)
+	defer withCurve(p.curve)()
+	g2Sub(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointG2) Neg(p1 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG2)
-	bls.G2Neg(&p.g, &pg1.g)
+	checkCurve(p.curve, pg1.curve)
+	defer withCurve(p.curve)()
+	g2Neg(&p.g, &pg1.g)
 	return p
 }
 
 func (p *pointG2) Mul(p1 abstract.Point, s abstract.Scalar) abstract.Point {
+	sc := s.(*scalar)
+	if p.constTime || sc.constTime {
+		return p.mulCT(p1, sc)
+	}
 	if p1 == nil {
-		p1 = newPointG2(p.generator).Base()
+		checkCurve(p.curve, sc.curve)
+		p.base.mul(&p.g, &sc.fe)
+		return p
 	}
-	sc := s.(*scalar)
 	pg1 := p1.(*pointG2)
-	bls.G2Mul(&p.g, &pg1.g, &sc.fe)
+	checkCurve(p.curve, pg1.curve, sc.curve)
+	defer withCurve(p.curve)()
+	g2Mul(&p.g, &pg1.g, &sc.fe)
+	return p
+}
+
+// mulCT sets p = s * p1, or s times the generator if p1 is nil, in constant
+// time.
+func (p *pointG2) mulCT(p1 abstract.Point, s *scalar) abstract.Point {
+	var x *g2
+	if p1 == nil {
+		checkCurve(p.curve, s.curve)
+		x = p.base.get()
+	} else {
+		pg1 := p1.(*pointG2)
+		checkCurve(p.curve, pg1.curve, s.curve)
+		x = &pg1.g
+	}
+	r := curveOrder(p.curve)
+	defer withCurve(p.curve)()
+	g2MulCT(&p.g, x, &s.fe, r)
 	return p
 }
 
-func (p *pointG2) MarshalBinary() (buff []byte, err error) {
+func (p *pointG2) MarshalBinary() ([]byte, error) {
+	return p.MarshalFormat(p.format)
+}
+
+// MarshalFormat implements FormatMarshaler.
+func (p *pointG2) MarshalFormat(f PointFormat) (buff []byte, err error) {
+	defer withCurve(p.curve)()
+	if f == Uncompressed {
+		return marshalUncompressed(&p.g, p.curve, 2)
+	}
 	return marshalBinary(&p.g)
 }
 
@@ -190,10 +331,40 @@ func (p *pointG2) MarshalTo(w io.Writer) (int, error) {
 }
 
 func (p *pointG2) UnmarshalBinary(buff []byte) error {
-	if buff == nil || len(buff) == 0 {
-		panic("aie aie aie")
+	_, err := p.UnmarshalFormat(buff)
+	return err
+}
+
+// UnmarshalFormat implements FormatUnmarshaler. The point is left unchanged
+// if buff is not the canonical encoding of an element of G2.
+func (p *pointG2) UnmarshalFormat(buff []byte) (PointFormat, error) {
+	f, err := formatOf(p.curve, 2, buff)
+	if err != nil {
+		return f, p.decodeError(ErrInvalidLength)
+	}
+	r := curveOrder(p.curve)
+	defer withCurve(p.curve)()
+	q := newG2(p.curve)
+	var canonical bool
+	if f == Uncompressed {
+		canonical, err = unmarshalUncompressed(&q, p.curve, 2, buff)
+	} else {
+		canonical, err = deserialize(&q, buff)
+	}
+	switch {
+	case err != nil:
+		return f, p.decodeError(ErrNotOnCurve)
+	case !canonical:
+		return f, p.decodeError(ErrNonCanonical)
+	case !g2InSubgroup(&q, r):
+		return f, p.decodeError(ErrNotInSubgroup)
 	}
-	return p.g.Deserialize(buff)
+	p.g = q
+	return f, nil
+}
+
+func (p *pointG2) decodeError(reason error) error {
+	return &DecodeError{Group: p.name(), Reason: reason}
 }
 
 func (p *pointG2) UnmarshalFrom(r io.Reader) (int, error) {
@@ -201,106 +372,135 @@ func (p *pointG2) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (p *pointG2) MarshalSize() int {
-	return bls.GetOpUnitSize() * 8 * 2
+	return pointSize(p.curve, 2, p.format)
 }
 
 func (p *pointG2) String() string {
+	defer withCurve(p.curve)()
 	return p.g.GetString(16)
 }
 
-func (p *pointG2) Pick(buff []byte, rand cipher.Stream) (abstract.Point, []byte) {
-	if buff != nil || len(buff) > 0 {
-		panic("point g2 don't embed yet")
-	}
-	buff = random.NonZeroBytes(32, rand)
-	if err := p.g.HashAndMapTo(buff); err != nil {
-		panic(err)
-	}
-	return p, nil
-
+// Pick returns a point of G2 chosen uniformly at random. No data can be
+// embedded in G2 points: G2 is a subgroup of index about p of the points of
+// the twist, so an x-coordinate built from data, as embed does for G1, gives
+// a point of G2 with probability about 1/p, and multiplying the point by the
+// cofactor to reach G2 would lose the data. Hence PickLen returns 0 and data
+// is returned as the remainder, unchanged.
+func (p *pointG2) Pick(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
+	return p.Embed(data, rand)
 }
 
 func (p *pointG2) PickLen() int {
-	// 8 bits for the randomness and 8 bits for the size of the message
-	return p.MarshalSize() - 1 - 1
+	return 0
 }
 
-func (p *pointG2) Embed(data []byte, rand cipher.Stream) abstract.Point {
-	panic("not working for the moment")
-	embed(p, data, rand)
-	return p
+// Embed is Pick.
+func (p *pointG2) Embed(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
+	s := newScalar(p.curve).Pick(rand).(*scalar)
+	p.base.mul(&p.g, &s.fe)
+	return p, data
 }
 
 func (p *pointG2) Clone() abstract.Point {
-	p2 := clone(p, newPointG2(p.generator))
-	return p2.(abstract.Point)
+	p2 := newPointG2(p.base, p.format)
+	p2.g = p.g
+	p2.constTime = p.constTime
+	return p2
 }
 
+// Data returns an empty slice since no data is embedded in G2 points, see
+// Pick.
 func (p *pointG2) Data() ([]byte, error) {
-	return data(p)
+	return []byte{}, nil
 }
 
 func (p *pointG2) Set(p2 abstract.Point) abstract.Point {
-	clone(p2, p)
+	pg := p2.(*pointG2)
+	checkCurve(p.curve, pg.curve)
+	p.g = pg.g
 	return p
 }
 
 type pointGT struct {
-	g bls.GT
-	p *Pairing
+	g         gt
+	p         *Pairing
+	constTime bool
 }
 
 func newPointGT(p *Pairing) *pointGT {
-	pg := &pointGT{g: bls.GT{}, p: p}
-	runtime.SetFinalizer(&pg.g, clear)
-	return pg
+	return &pointGT{g: newGT(p.curve), p: p}
 }
 
 func (p *pointGT) Pairing(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointG1)
 	pg2 := p2.(*pointG2)
-	bls.Pairing(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.p.curve, pg1.curve, pg2.curve)
+	defer withCurve(p.p.curve)()
+	pairing(&p.g, &pg1.g, &pg2.g)
+	return p
+}
+
+func (p *pointGT) PairingProduct(p1s, p2s []abstract.Point) abstract.Point {
+	if len(p1s) != len(p2s) {
+		panic("pairing: product of slices of different lengths")
+	}
+	ps := make([]*g1, len(p1s))
+	qs := make([]*g2, len(p2s))
+	for i := range p1s {
+		pg1 := p1s[i].(*pointG1)
+		pg2 := p2s[i].(*pointG2)
+		checkCurve(p.p.curve, pg1.curve, pg2.curve)
+		ps[i], qs[i] = &pg1.g, &pg2.g
+	}
+	defer withCurve(p.p.curve)()
+	pairingProduct(&p.g, ps, qs)
 	return p
 }
 
 func (p *pointGT) Equal(p2 abstract.Point) bool {
 	pg := p2.(*pointGT)
+	checkCurve(p.p.curve, pg.p.curve)
+	defer withCurve(p.p.curve)()
 	return p.g.IsEqual(&pg.g)
 }
 
 func (p *pointGT) Null() abstract.Point {
-	// multiplicative identity
+	defer withCurve(p.p.curve)()
+	// GT is a multiplicative group
 	p.g.SetInt64(1)
-	//p.g.Clear()
 	return p
 }
 
 // Base point for GT is the point computed using the pairing operation
-// over the base point of G1 and G2.
-// TODO precompute the pairing for each suite...
+// over the base point of G1 and G2. It is computed once per Pairing.
 func (p *pointGT) Base() abstract.Point {
-	g1 := p.p.G1().Point().Base()
-	g2 := p.p.G2().Point().Base()
-	return p.Pairing(g1, g2)
+	p.g = *p.p.gt.base.get(p.p.g1.base, p.p.g2.base)
+	return p
 }
 
 func (p *pointGT) Add(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointGT)
 	pg2 := p2.(*pointGT)
-	bls.GTMul(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.p.curve, pg1.p.curve, pg2.p.curve)
+	defer withCurve(p.p.curve)()
+	gtMul(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointGT) Sub(p1, p2 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointGT)
 	pg2 := p2.(*pointGT)
-	bls.GTDiv(&p.g, &pg1.g, &pg2.g)
+	checkCurve(p.p.curve, pg1.p.curve, pg2.p.curve)
+	defer withCurve(p.p.curve)()
+	gtDiv(&p.g, &pg1.g, &pg2.g)
 	return p
 }
 
 func (p *pointGT) Neg(p1 abstract.Point) abstract.Point {
 	pg1 := p1.(*pointGT)
-	bls.GTInv(&p.g, &pg1.g)
+	checkCurve(p.p.curve, pg1.p.curve)
+	defer withCurve(p.p.curve)()
+	gtInv(&p.g, &pg1.g)
 	return p
 }
 
@@ -310,11 +510,20 @@ func (p *pointGT) Mul(p1 abstract.Point, s abstract.Scalar) abstract.Point {
 	}
 	sc := s.(*scalar)
 	pg1 := p1.(*pointGT)
-	bls.GTPow(&p.g, &pg1.g, &sc.fe)
+	checkCurve(p.p.curve, pg1.p.curve, sc.curve)
+	if p.constTime || sc.constTime {
+		r := curveOrder(p.p.curve)
+		defer withCurve(p.p.curve)()
+		gtPowCT(&p.g, &pg1.g, &sc.fe, r)
+		return p
+	}
+	defer withCurve(p.p.curve)()
+	gtPow(&p.g, &pg1.g, &sc.fe)
 	return p
 }
 
 func (p *pointGT) MarshalBinary() (buff []byte, err error) {
+	defer withCurve(p.p.curve)()
 	return marshalBinary(&p.g)
 }
 
@@ -322,8 +531,31 @@ func (p *pointGT) MarshalTo(w io.Writer) (int, error) {
 	return group.PointMarshalTo(p, w)
 }
 
+// UnmarshalBinary decodes buff and checks that it is the canonical encoding
+// of an element of the order r subgroup of the target field. The point is
+// left unchanged otherwise.
 func (p *pointGT) UnmarshalBinary(buff []byte) error {
-	return p.g.Deserialize(buff)
+	if len(buff) != p.MarshalSize() {
+		return p.decodeError(ErrInvalidLength)
+	}
+	r := curveOrder(p.p.curve)
+	defer withCurve(p.p.curve)()
+	q := newGT(p.p.curve)
+	canonical, err := deserialize(&q, buff)
+	switch {
+	case err != nil:
+		return p.decodeError(ErrNotOnCurve)
+	case !canonical:
+		return p.decodeError(ErrNonCanonical)
+	case !gtInSubgroup(&q, r):
+		return p.decodeError(ErrNotInSubgroup)
+	}
+	p.g = q
+	return nil
+}
+
+func (p *pointGT) decodeError(reason error) error {
+	return &DecodeError{Group: p.name(), Reason: reason}
 }
 
 func (p *pointGT) UnmarshalFrom(r io.Reader) (int, error) {
@@ -331,38 +563,50 @@ func (p *pointGT) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (p *pointGT) MarshalSize() int {
-	return bls.GetOpUnitSize() * 8 * 12
+	return opUnitSize(p.p.curve) * 8 * 12
 }
 
 func (p *pointGT) String() string {
+	defer withCurve(p.p.curve)()
 	return p.g.GetString(16)
 }
 
-func (p *pointGT) Pick(buff []byte, rand cipher.Stream) (abstract.Point, []byte) {
-	return p.Embed(buff, rand)
+// Pick returns an element of GT chosen uniformly at random. As for G2, no data
+// can be embedded in GT: it is the subgroup of order r of the multiplicative
+// group of the degree 12 extension field, so the encodings built from data
+// are almost never in it. Hence PickLen returns 0 and data is returned as the
+// remainder, unchanged.
+func (p *pointGT) Pick(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
+	return p.Embed(data, rand)
 }
 
 func (p *pointGT) PickLen() int {
-	// 8 bits for the randomness and 8 bits for the size of the message
-	return (bls.GetOpUnitSize() * 8 * 12) - 1 - 1
+	return 0
 }
 
+// Embed is Pick.
 func (p *pointGT) Embed(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
-	res := embed(p, data, rand)
-	return p, res
+	p.Mul(nil, newScalar(p.p.curve).Pick(rand))
+	return p, data
 }
 
 func (p *pointGT) Clone() abstract.Point {
-	p2 := clone(p, newPointGT(p.p))
-	return p2.(abstract.Point)
+	p2 := newPointGT(p.p)
+	p2.g = p.g
+	p2.constTime = p.constTime
+	return p2
 }
 
+// Data returns an empty slice since no data is embedded in GT elements, see
+// Pick.
 func (p *pointGT) Data() ([]byte, error) {
-	return data(p)
+	return []byte{}, nil
 }
 
 func (p *pointGT) Set(p2 abstract.Point) abstract.Point {
-	clone(p2, p)
+	pg := p2.(*pointGT)
+	checkCurve(p.p.curve, pg.p.curve)
+	p.g = pg.g
 	return p
 }
 
@@ -375,10 +619,6 @@ type serializable interface {
 	Serialize() []byte
 }
 
-type clearable interface {
-	Clear()
-}
-
 func marshalBinary(p serializable) (buff []byte, err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -391,44 +631,41 @@ func marshalBinary(p serializable) (buff []byte, err error) {
 
 }
 
+// embedAttempts bounds the number of encodings tried by embed. About one
+// random encoding out of seven is a valid point of G1 on Fp254BNb so it is
+// never reached in practice.
+const embedAttempts = 1000
+
+var errEmbed = errors.New("pbc: no valid point found to embed the data")
+
+// embed sets p to a point whose compressed encoding of PickLen() + 2 bytes is
+//
+//	len | data[:len] | random bytes
+//
+// where len is a single byte, at most PickLen(), and returns the data that
+// did not fit in the point. The random bytes are drawn again until the
+// encoding is a valid point, at most embedAttempts times. When data is nil, p
+// is a random point.
 func embed(p pbcPoint, data []byte, rand cipher.Stream) []byte {
-	buffSize := p.MarshalSize() // how much data + len + random can we embed
-	embedSize := p.PickLen()    // how much data can we embed
+	embedSize := p.PickLen()      // how much data can we embed
+	buffSize := embedSize + 1 + 1 // how much data + len + random can we embed
 	if embedSize > len(data) {
 		embedSize = len(data)
 	}
 
-	for {
+	for i := 0; i < embedAttempts; i++ {
 		// try filling in random bytes
-		// XXX could be optimized by keeping one buffer and doing the "random"
-		// part ourselves.
 		buff := random.NonZeroBytes(buffSize, rand)
 		if data != nil {
 			buff[0] = byte(embedSize)       // encode length in low 8 bits
 			copy(buff[1:1+embedSize], data) // copy data
 		}
 
-		err := p.UnmarshalBinary(buff)
-		if err != nil {
-			// no luck, try again
-			continue
+		if err := p.UnmarshalBinary(buff); err == nil {
+			return data[embedSize:]
 		}
-
-		// Points live in a prime order curve so no cofactor-thing needed. All ok.
-		return data[embedSize:]
-	}
-}
-
-func clone(p, p2 pbcPoint) pbcPoint {
-	buff, err := p.MarshalBinary()
-	if err != nil {
-		panic(err)
 	}
-	err = p2.UnmarshalBinary(buff)
-	if err != nil {
-		panic(err)
-	}
-	return p2
+	panic(errEmbed)
 }
 
 func data(p pbcPoint) ([]byte, error) {
@@ -439,20 +676,3 @@ func data(p pbcPoint) ([]byte, error) {
 	}
 	return buff[1 : 1+dl], nil
 }
-
-func clear(p clearable) {
-	p.Clear()
-}
-
-var ErrVarTime = errors.New("no constant time implementation available")
-
-func (p *pointG1) SetVarTime(varTime bool) error {
-	return ErrVarTime
-}
-
-func (p *pointG2) SetVarTime(varTime bool) error {
-	return ErrVarTime
-}
-func (p *pointGT) SetVarTime(varTime bool) error {
-	return ErrVarTime
-}
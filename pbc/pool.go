@@ -0,0 +1,118 @@
+package pbc
+
+import (
+	"sync"
+	"unsafe"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// Releaser is implemented by the groups of a Pairing. Release hands points
+// and scalars of the group that will not be used anymore back to it, so the
+// next calls to Point and Scalar reuse them instead of allocating. Released
+// objects are wiped first, since points may hold values derived from secrets
+// such as partial signatures too. Releasing is optional: the objects that are
+// not released are simply collected by the garbage collector. An object must
+// not be used, nor released twice, once released.
+type Releaser interface {
+	Release(objs ...interface{})
+}
+
+// Release gives objs back to g if it implements Releaser, and does nothing
+// otherwise.
+func Release(g abstract.Group, objs ...interface{}) {
+	if r, ok := g.(Releaser); ok {
+		r.Release(objs...)
+	}
+}
+
+// pools holds the released objects of the groups of a Pairing. The scalars
+// are shared by the three groups.
+type pools struct {
+	g1, g2, gt, scalars sync.Pool
+}
+
+func (g *g1group) Release(objs ...interface{}) {
+	for _, o := range objs {
+		switch v := o.(type) {
+		case *pointG1:
+			checkCurve(g.curve, v.curve)
+			zeroize(unsafe.Pointer(&v.g), unsafe.Sizeof(v.g))
+			g.pools.g1.Put(v)
+		case *scalar:
+			g.releaseScalar(v)
+		}
+	}
+}
+
+func (g *g2group) Release(objs ...interface{}) {
+	for _, o := range objs {
+		switch v := o.(type) {
+		case *pointG2:
+			checkCurve(g.curve, v.curve)
+			zeroize(unsafe.Pointer(&v.g), unsafe.Sizeof(v.g))
+			g.pools.g2.Put(v)
+		case *scalar:
+			g.releaseScalar(v)
+		}
+	}
+}
+
+func (g *gtgroup) Release(objs ...interface{}) {
+	for _, o := range objs {
+		switch v := o.(type) {
+		case *pointGT:
+			checkCurve(g.curve, v.p.curve)
+			zeroize(unsafe.Pointer(&v.g), unsafe.Sizeof(v.g))
+			g.pools.gt.Put(v)
+		case *scalar:
+			g.releaseScalar(v)
+		}
+	}
+}
+
+func (c *common) releaseScalar(s *scalar) {
+	checkCurve(c.curve, s.curve)
+	s.Destroy()
+	c.pools.scalars.Put(s)
+}
+
+// The functions below return a released object if there is one, reset to
+// the state of a new object, or a new object otherwise.
+
+func (g *g1group) newPoint() *pointG1 {
+	if p, ok := g.pools.g1.Get().(*pointG1); ok {
+		p.g = newG1(g.curve)
+		p.format = g.format
+		p.constTime = false
+		return p
+	}
+	return newPointG1(g.base, g.format)
+}
+
+func (g *g2group) newPoint() *pointG2 {
+	if p, ok := g.pools.g2.Get().(*pointG2); ok {
+		p.g = newG2(g.curve)
+		p.format = g.format
+		p.constTime = false
+		return p
+	}
+	return newPointG2(g.base, g.format)
+}
+
+func (g *gtgroup) newPoint() *pointGT {
+	if p, ok := g.pools.gt.Get().(*pointGT); ok {
+		p.g = newGT(g.curve)
+		p.constTime = false
+		return p
+	}
+	return newPointGT(g.p)
+}
+
+func (c *common) newScalar() *scalar {
+	if s, ok := c.pools.scalars.Get().(*scalar); ok {
+		s.constTime = false
+		return s
+	}
+	return newScalar(c.curve)
+}
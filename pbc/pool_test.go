@@ -0,0 +1,58 @@
+package pbc
+
+import (
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelease(t *testing.T) {
+	p := NewPairingFp254BNb()
+	p.SetPointFormat(Uncompressed)
+	for _, g := range []abstract.Suite{p.G1(), p.G2(), p.GT()} {
+		s := g.Scalar().Pick(random.Stream)
+		s.(VarTimer).SetVarTime(false)
+		pt := g.Point().Mul(nil, s)
+		pt.(VarTimer).SetVarTime(false)
+		Release(g, pt, s)
+		// released objects are wiped right away
+		require.True(t, s.Equal(g.Scalar().Zero()))
+		switch v := pt.(type) {
+		case *pointG1:
+			require.Equal(t, g1{}, v.g)
+		case *pointG2:
+			require.Equal(t, g2{}, v.g)
+		case *pointGT:
+			require.Equal(t, gt{}, v.g)
+		}
+
+		// whether or not the objects come from the pool, they behave as new
+		// ones
+		for i := 0; i < 2; i++ {
+			s2 := g.Scalar()
+			require.False(t, s2.(*scalar).constTime)
+			require.True(t, g.Point().Mul(nil, s2.SetInt64(2)).Equal(
+				g.Point().Add(g.Point().Base(), g.Point().Base())))
+			require.Equal(t, g.PointLen(), g.Point().MarshalSize())
+		}
+	}
+
+	// objects of other groups are ignored, the other curves are rejected
+	Release(p.G1(), p.G2().Point(), "not a point")
+	if Supported(CurveFp382_1) {
+		require.Panics(t, func() {
+			Release(p.G1(), NewPairingFp382_1().G1().Point())
+		})
+	}
+}
+
+func BenchmarkG1PointRelease(b *testing.B) {
+	g := NewPairingFp254BNb().G1()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Release(g, g.Point().Base())
+	}
+}
@@ -3,26 +3,33 @@ package pbc
 import (
 	"crypto/cipher"
 	"io"
-	"runtime"
+	"math/big"
 
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/random"
 
-	"github.com/dfinity/go-dfinity-crypto/bls"
-
 	"gopkg.in/dedis/crypto.v0/group"
 )
 
+// BigScalar is implemented by the scalars of this package to convert them from
+// and to integers.
+type BigScalar interface {
+	// SetBig sets the scalar to b modulo the order of the groups.
+	SetBig(b *big.Int) abstract.Scalar
+	// Big returns the value of the scalar, in [0, r) where r is the order of
+	// the groups.
+	Big() *big.Int
+}
+
 type scalar struct {
-	fe bls.Fr
+	fe        fr
+	curve     int
+	constTime bool
 }
 
-// NewScalar returns a non initialized abstract.Scalar implementation to use with
-// PBC groups.
-func NewScalar() abstract.Scalar {
-	s := &scalar{fe: bls.Fr{}}
-	runtime.SetFinalizer(s, clearScalar)
-	return s
+// newScalar returns a non initialized scalar for the given curve.
+func newScalar(curve int) *scalar {
+	return &scalar{fe: newFr(curve), curve: curve}
 }
 
 func (s *scalar) Zero() abstract.Scalar {
@@ -34,69 +41,100 @@ func (s *scalar) One() abstract.Scalar {
 }
 
 func (s *scalar) Equal(s2 abstract.Scalar) bool {
-	return s.fe.IsEqual(&s2.(*scalar).fe)
+	sc2 := s2.(*scalar)
+	checkCurve(s.curve, sc2.curve)
+	defer withCurve(s.curve)()
+	return s.fe.IsEqual(&sc2.fe)
 }
 
 func (s *scalar) Neg(s2 abstract.Scalar) abstract.Scalar {
-	bls.FrNeg(&s.fe, &s2.(*scalar).fe)
+	sc2 := s2.(*scalar)
+	checkCurve(s.curve, sc2.curve)
+	defer withCurve(s.curve)()
+	frNeg(&s.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) Add(s1, s2 abstract.Scalar) abstract.Scalar {
 	sc1 := s1.(*scalar)
 	sc2 := s2.(*scalar)
-	bls.FrAdd(&s.fe, &sc1.fe, &sc2.fe)
+	checkCurve(s.curve, sc1.curve, sc2.curve)
+	defer withCurve(s.curve)()
+	frAdd(&s.fe, &sc1.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) Sub(s1, s2 abstract.Scalar) abstract.Scalar {
 	sc1 := s1.(*scalar)
 	sc2 := s2.(*scalar)
-	bls.FrSub(&s.fe, &sc1.fe, &sc2.fe)
+	checkCurve(s.curve, sc1.curve, sc2.curve)
+	defer withCurve(s.curve)()
+	frSub(&s.fe, &sc1.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) Mul(s1, s2 abstract.Scalar) abstract.Scalar {
 	sc1 := s1.(*scalar)
 	sc2 := s2.(*scalar)
-	bls.FrMul(&s.fe, &sc1.fe, &sc2.fe)
+	checkCurve(s.curve, sc1.curve, sc2.curve)
+	defer withCurve(s.curve)()
+	frMul(&s.fe, &sc1.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) Div(s1, s2 abstract.Scalar) abstract.Scalar {
 	sc1 := s1.(*scalar)
 	sc2 := s2.(*scalar)
-	bls.FrDiv(&s.fe, &sc1.fe, &sc2.fe)
+	checkCurve(s.curve, sc1.curve, sc2.curve)
+	if s.constTime || sc1.constTime || sc2.constTime {
+		r := curveOrder(s.curve)
+		defer withCurve(s.curve)()
+		var inv fr
+		frInvCT(&inv, &sc2.fe, r)
+		frMul(&s.fe, &sc1.fe, &inv)
+		return s
+	}
+	defer withCurve(s.curve)()
+	frDiv(&s.fe, &sc1.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) Inv(s2 abstract.Scalar) abstract.Scalar {
 	sc2 := s2.(*scalar)
-	bls.FrInv(&s.fe, &sc2.fe)
+	checkCurve(s.curve, sc2.curve)
+	if s.constTime || sc2.constTime {
+		r := curveOrder(s.curve)
+		defer withCurve(s.curve)()
+		frInvCT(&s.fe, &sc2.fe, r)
+		return s
+	}
+	defer withCurve(s.curve)()
+	frInv(&s.fe, &sc2.fe)
 	return s
 }
 
 func (s *scalar) SetInt64(i int64) abstract.Scalar {
+	defer withCurve(s.curve)()
 	s.fe.SetInt64(i)
 	return s
 }
 
 func (s *scalar) Set(a abstract.Scalar) abstract.Scalar {
-	buff, _ := a.MarshalBinary()
-	err := s.UnmarshalBinary(buff)
-	if err != nil {
-		panic(err)
-	}
+	sc := a.(*scalar)
+	checkCurve(s.curve, sc.curve)
+	s.fe = sc.fe
 	return s
 }
 
 func (s *scalar) Clone() abstract.Scalar {
-	s2 := NewScalar()
+	s2 := newScalar(s.curve)
 	s2.Set(s)
+	s2.constTime = s.constTime
 	return s2
 }
 
 func (s *scalar) MarshalBinary() (buff []byte, err error) {
+	defer withCurve(s.curve)()
 	defer func() {
 		if e := recover(); e != nil {
 			buff = nil
@@ -112,8 +150,25 @@ func (s *scalar) MarshalTo(w io.Writer) (int, error) {
 	return group.ScalarMarshalTo(s, w)
 }
 
+// UnmarshalBinary decodes buff and checks that it is the canonical encoding of
+// a scalar, i.e. that it is strictly lower than the order of the groups. The
+// scalar is left unchanged otherwise.
 func (s *scalar) UnmarshalBinary(buff []byte) error {
-	return s.fe.Deserialize(buff)
+	if len(buff) != scalarSize(s.curve) {
+		return s.decodeError(ErrInvalidLength)
+	}
+	defer withCurve(s.curve)()
+	fe := newFr(s.curve)
+	canonical, err := deserialize(&fe, buff)
+	if err != nil || !canonical {
+		return s.decodeError(ErrOutOfRange)
+	}
+	s.fe = fe
+	return nil
+}
+
+func (s *scalar) decodeError(reason error) error {
+	return &DecodeError{Group: s.name(), Reason: reason}
 }
 
 func (s *scalar) UnmarshalFrom(r io.Reader) (int, error) {
@@ -121,15 +176,15 @@ func (s *scalar) UnmarshalFrom(r io.Reader) (int, error) {
 }
 
 func (s *scalar) MarshalSize() int {
-	// XXX hackish version. Better to know the usual length.
-	buff, _ := s.MarshalBinary()
-	return len(buff)
+	return scalarSize(s.curve)
 }
 
+// SetBytes interprets buff as a big-endian integer of any length and sets s to
+// its value modulo the order of the groups, so it can be given the output of a
+// hash function directly. Note that it is not the inverse of Bytes, which
+// returns the little-endian encoding of MarshalBinary.
 func (s *scalar) SetBytes(buff []byte) abstract.Scalar {
-	// XXX Maybe later have a "real" setbytes
-	s.UnmarshalBinary(buff)
-	return s
+	return s.SetBig(new(big.Int).SetBytes(buff))
 }
 
 func (s *scalar) Bytes() []byte {
@@ -137,25 +192,30 @@ func (s *scalar) Bytes() []byte {
 	return buff
 }
 
+// Pick sets s to a scalar chosen uniformly at random in [0, r), sampling by
+// rejection from rand.
 func (s *scalar) Pick(rand cipher.Stream) abstract.Scalar {
-	buff := random.NonZeroBytes(s.MarshalSize(), rand)
-	err := s.fe.SetLittleEndian(buff)
-	if err != nil {
+	return s.SetBig(random.Int(curveOrder(s.curve), rand))
+}
+
+func (s *scalar) SetBig(b *big.Int) abstract.Scalar {
+	v := new(big.Int).Mod(b, curveOrder(s.curve))
+	buff := make([]byte, s.MarshalSize())
+	putLittleEndian(buff, v)
+	defer withCurve(s.curve)()
+	if err := s.fe.Deserialize(buff); err != nil {
 		panic(err)
 	}
 	return s
 }
 
+func (s *scalar) Big() *big.Int {
+	defer withCurve(s.curve)()
+	return getLittleEndian(s.fe.Serialize())
+}
+
 func (s *scalar) String() string {
+	defer withCurve(s.curve)()
 	// return hexadecimal string
 	return s.fe.GetString(16)
 }
-
-// clearScalar frees the memory allocated by the C library.
-func clearScalar(s *scalar) {
-	s.fe.Clear()
-}
-
-func (s *scalar) SetVarTime(varTime bool) error {
-	return ErrVarTime
-}
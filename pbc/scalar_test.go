@@ -0,0 +1,88 @@
+package pbc
+
+import (
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarBig(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		g := NewPairing(curve).G2()
+		r := curveOrder(curve)
+
+		s := g.Scalar().Pick(random.Stream)
+		b := s.(BigScalar).Big()
+		require.True(t, b.Cmp(r) < 0)
+		require.True(t, g.Scalar().(BigScalar).SetBig(b).Equal(s))
+
+		minusOne := new(big.Int).Sub(r, big.NewInt(1))
+		require.Equal(t, 0, g.Scalar().SetInt64(-1).(BigScalar).Big().Cmp(minusOne))
+		require.True(t, g.Scalar().(BigScalar).SetBig(big.NewInt(-1)).Equal(g.Scalar().SetInt64(-1)))
+		require.True(t, g.Scalar().(BigScalar).SetBig(r).Equal(g.Scalar().Zero()))
+
+		buff, err := s.MarshalBinary()
+		require.Nil(t, err)
+		require.Len(t, buff, s.MarshalSize())
+		require.Equal(t, s.MarshalSize(), g.ScalarLen())
+	}
+}
+
+func TestScalarSetBytes(t *testing.T) {
+	g := NewPairingFp254BNb().G2()
+	r := curveOrder(CurveFp254BNb)
+
+	// big-endian and reduced modulo r, whatever the length
+	require.True(t, g.Scalar().SetBytes([]byte{1, 0}).Equal(g.Scalar().SetInt64(256)))
+	require.True(t, g.Scalar().SetBytes(nil).Equal(g.Scalar().Zero()))
+	rPlus2 := new(big.Int).Add(r, big.NewInt(2)).Bytes()
+	require.True(t, g.Scalar().SetBytes(rPlus2).Equal(g.Scalar().SetInt64(2)))
+
+	digest := sha512.Sum512([]byte("hello"))
+	exp := new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), r)
+	require.Equal(t, 0, g.Scalar().SetBytes(digest[:]).(BigScalar).Big().Cmp(exp))
+}
+
+func TestScalarPick(t *testing.T) {
+	g := NewPairingFp254BNb().G2()
+	r := curveOrder(CurveFp254BNb)
+	// the top bit of r is set as often as it should: the picked scalars are
+	// above r/2 about half of the time
+	half := new(big.Int).Rsh(r, 1)
+	above := 0
+	n := 2000
+	for i := 0; i < n; i++ {
+		if g.Scalar().Pick(random.Stream).(BigScalar).Big().Cmp(half) > 0 {
+			above++
+		}
+	}
+	require.InDelta(t, n/2, above, float64(n)/10)
+}
+
+func TestScalarDestroy(t *testing.T) {
+	g := NewPairingFp254BNb().G1()
+	s := g.Scalar().Pick(random.Stream)
+	c := s.Clone()
+	s.(Destroyer).Destroy()
+	require.True(t, s.Equal(g.Scalar().Zero()))
+	require.False(t, c.Equal(g.Scalar().Zero()))
+
+	DestroyScalar(c)
+	require.True(t, c.Equal(g.Scalar().Zero()))
+	DestroyScalar(nil)
+
+	// a destroyed scalar can be reused
+	s.SetInt64(3)
+	require.True(t, s.Equal(g.Scalar().SetInt64(3)))
+
+	buff := []byte{1, 2, 3}
+	Zeroize(buff)
+	require.Equal(t, []byte{0, 0, 0}, buff)
+}
@@ -0,0 +1,312 @@
+package pbc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// The text form of points and scalars is the name of their curve and group
+// followed by the hexadecimal encoding of MarshalBinary:
+//
+//	Fp254Nb_G2:4b1e...
+//	BLS12_381_Fr:0c3f...
+//
+// so a value read from a configuration file or a log can not be mistaken for
+// an element of another group. It is used by MarshalText and, as a JSON
+// string, by MarshalJSON. ParsePoint and ParseScalar decode it without
+// knowing the group in advance.
+
+// Reasons for which a text form can be rejected, wrapped in a *DecodeError.
+var (
+	ErrTextTag      = errors.New("text tagged with another curve or group")
+	ErrTextEncoding = errors.New("invalid text encoding")
+)
+
+// textTagSeparator separates the group name from the encoding.
+const textTagSeparator = ":"
+
+func marshalText(name string, m abstract.Marshaling) ([]byte, error) {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(name + textTagSeparator + hex.EncodeToString(buff)), nil
+}
+
+// splitText returns the group name and the decoded bytes of a text form.
+func splitText(text []byte) (string, []byte, error) {
+	parts := strings.SplitN(string(text), textTagSeparator, 2)
+	if len(parts) != 2 {
+		return "", nil, &DecodeError{Group: "text", Reason: ErrTextEncoding}
+	}
+	buff, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, &DecodeError{Group: parts[0], Reason: ErrTextEncoding}
+	}
+	return parts[0], buff, nil
+}
+
+// unmarshalText decodes text into m if it is tagged with name.
+func unmarshalText(name string, m abstract.Marshaling, text []byte) error {
+	tag, buff, err := splitText(text)
+	if err != nil {
+		return err
+	}
+	if tag != name {
+		return &DecodeError{Group: name, Reason: ErrTextTag}
+	}
+	return m.UnmarshalBinary(buff)
+}
+
+func marshalJSON(name string, m abstract.Marshaling) ([]byte, error) {
+	text, err := marshalText(name, m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func unmarshalJSON(name string, m abstract.Marshaling, buff []byte) error {
+	var text string
+	if err := json.Unmarshal(buff, &text); err != nil {
+		return err
+	}
+	return unmarshalText(name, m, []byte(text))
+}
+
+// parseTag returns the curve and the group, "G1", "G2", "GT" or "Fr", of a
+// group name.
+func parseTag(tag string) (int, string, bool) {
+	i := strings.LastIndex(tag, "_")
+	if i < 0 {
+		return 0, "", false
+	}
+	curve, ok := curveByName(tag[:i])
+	if !ok || !Supported(curve) {
+		return 0, "", false
+	}
+	return curve, tag[i+1:], true
+}
+
+// ParsePoint decodes the text form of a point of any group of any supported
+// curve. The point is created with the point format of the encoding.
+func ParsePoint(text []byte) (abstract.Point, error) {
+	tag, buff, err := splitText(text)
+	if err != nil {
+		return nil, err
+	}
+	curve, group, ok := parseTag(tag)
+	if !ok {
+		return nil, &DecodeError{Group: tag, Reason: ErrTextTag}
+	}
+	var p abstract.Point
+	switch group {
+	case "G1", "G2":
+		coords := 1
+		if group == "G2" {
+			coords = 2
+		}
+		format, err := formatOf(curve, coords, buff)
+		if err != nil {
+			return nil, &DecodeError{Group: tag, Reason: ErrInvalidLength}
+		}
+		pairing := envelopePairing(curve, format)
+		if group == "G1" {
+			p = pairing.G1().Point()
+		} else {
+			p = pairing.G2().Point()
+		}
+	case "GT":
+		p = envelopePairing(curve, Compressed).GT().Point()
+	default:
+		return nil, &DecodeError{Group: tag, Reason: ErrTextTag}
+	}
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseScalar decodes the text form of a scalar of any supported curve.
+func ParseScalar(text []byte) (abstract.Scalar, error) {
+	tag, buff, err := splitText(text)
+	if err != nil {
+		return nil, err
+	}
+	curve, group, ok := parseTag(tag)
+	if !ok || group != "Fr" {
+		return nil, &DecodeError{Group: tag, Reason: ErrTextTag}
+	}
+	s := newScalar(curve)
+	if err := s.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// groupOf returns the group of a point created by a Pairing.
+func groupOf(p abstract.Point) (abstract.Group, error) {
+	switch pt := p.(type) {
+	case *pointG1:
+		return envelopePairing(pt.curve, pt.format).G1(), nil
+	case *pointG2:
+		return envelopePairing(pt.curve, pt.format).G2(), nil
+	case *pointGT:
+		return pt.p.GT(), nil
+	}
+	return nil, errors.New("pbc: not a pbc point")
+}
+
+// PubPoly wraps a share.PubPoly, whose fields are not exported, to give it a
+// text form: the text forms of the base point and of the commitments,
+// separated by spaces. A nil base point, standing for the generator of the
+// group, is written as the generator. The group of the polynomial is the one
+// of the base point, so any PubPoly of the pbc groups can be decoded into a
+// zero PubPoly.
+type PubPoly struct {
+	*share.PubPoly
+}
+
+func (p PubPoly) MarshalText() ([]byte, error) {
+	base, commits := p.Info()
+	if len(commits) == 0 {
+		return nil, errors.New("pbc: polynomial without commitments")
+	}
+	if base == nil {
+		g, err := groupOf(commits[0])
+		if err != nil {
+			return nil, err
+		}
+		base = g.Point().Base()
+	}
+	var parts []string
+	for _, pt := range append([]abstract.Point{base}, commits...) {
+		text, err := pt.(textMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, string(text))
+	}
+	return []byte(strings.Join(parts, " ")), nil
+}
+
+func (p *PubPoly) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) < 2 {
+		return &DecodeError{Group: "PubPoly", Reason: ErrTextEncoding}
+	}
+	points := make([]abstract.Point, len(fields))
+	for i, f := range fields {
+		pt, err := ParsePoint([]byte(f))
+		if err != nil {
+			return err
+		}
+		points[i] = pt
+	}
+	tag := strings.SplitN(fields[0], textTagSeparator, 2)[0]
+	for _, f := range fields[1:] {
+		if strings.SplitN(f, textTagSeparator, 2)[0] != tag {
+			return &DecodeError{Group: tag, Reason: ErrTextTag}
+		}
+	}
+	g, err := groupOf(points[0])
+	if err != nil {
+		return err
+	}
+	p.PubPoly = share.NewPubPoly(g, points[0], points[1:])
+	return nil
+}
+
+type textMarshaler interface {
+	MarshalText() ([]byte, error)
+}
+
+func (p *pointG1) name() string {
+	return curveName(p.curve) + "_G1"
+}
+
+func (p *pointG1) MarshalText() ([]byte, error) {
+	return marshalText(p.name(), p)
+}
+
+// UnmarshalText decodes the text form of a point of G1 of the same curve, in
+// any point format.
+func (p *pointG1) UnmarshalText(text []byte) error {
+	return unmarshalText(p.name(), p, text)
+}
+
+func (p *pointG1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.name(), p)
+}
+
+func (p *pointG1) UnmarshalJSON(buff []byte) error {
+	return unmarshalJSON(p.name(), p, buff)
+}
+
+func (p *pointG2) name() string {
+	return curveName(p.curve) + "_G2"
+}
+
+func (p *pointG2) MarshalText() ([]byte, error) {
+	return marshalText(p.name(), p)
+}
+
+// UnmarshalText decodes the text form of a point of G2 of the same curve, in
+// any point format.
+func (p *pointG2) UnmarshalText(text []byte) error {
+	return unmarshalText(p.name(), p, text)
+}
+
+func (p *pointG2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.name(), p)
+}
+
+func (p *pointG2) UnmarshalJSON(buff []byte) error {
+	return unmarshalJSON(p.name(), p, buff)
+}
+
+func (p *pointGT) name() string {
+	return curveName(p.p.curve) + "_GT"
+}
+
+func (p *pointGT) MarshalText() ([]byte, error) {
+	return marshalText(p.name(), p)
+}
+
+func (p *pointGT) UnmarshalText(text []byte) error {
+	return unmarshalText(p.name(), p, text)
+}
+
+func (p *pointGT) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.name(), p)
+}
+
+func (p *pointGT) UnmarshalJSON(buff []byte) error {
+	return unmarshalJSON(p.name(), p, buff)
+}
+
+func (s *scalar) name() string {
+	return curveName(s.curve) + "_Fr"
+}
+
+func (s *scalar) MarshalText() ([]byte, error) {
+	return marshalText(s.name(), s)
+}
+
+// UnmarshalText decodes the text form of a scalar of the same curve.
+func (s *scalar) UnmarshalText(text []byte) error {
+	return unmarshalText(s.name(), s, text)
+}
+
+func (s *scalar) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.name(), s)
+}
+
+func (s *scalar) UnmarshalJSON(buff []byte) error {
+	return unmarshalJSON(s.name(), s, buff)
+}
@@ -0,0 +1,107 @@
+package pbc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	for _, curve := range []int{CurveFp254BNb, CurveFp382_1, CurveFp382_2, CurveBLS12_381} {
+		if !Supported(curve) {
+			continue
+		}
+		for _, format := range []PointFormat{Compressed, Uncompressed} {
+			p := NewPairing(curve)
+			p.SetPointFormat(format)
+			for _, g := range []abstract.Group{p.G1(), p.G2(), p.GT()} {
+				pt := g.Point().Mul(nil, g.Scalar().Pick(random.Stream))
+				text, err := pt.(textMarshaler).MarshalText()
+				require.Nil(t, err)
+				require.True(t, strings.HasPrefix(string(text), g.String()+":"), "%s", text)
+
+				dec := g.Point()
+				require.Nil(t, dec.(interface {
+					UnmarshalText([]byte) error
+				}).UnmarshalText(text))
+				require.True(t, dec.Equal(pt), "%s", g)
+
+				parsed, err := ParsePoint(text)
+				require.Nil(t, err)
+				require.True(t, parsed.Equal(pt), "%s", g)
+				require.Equal(t, pt.MarshalSize(), parsed.MarshalSize())
+
+				buff, err := json.Marshal(pt)
+				require.Nil(t, err)
+				require.Equal(t, `"`+string(text)+`"`, string(buff))
+				dec = g.Point()
+				require.Nil(t, json.Unmarshal(buff, dec))
+				require.True(t, dec.Equal(pt), "%s", g)
+			}
+		}
+
+		s := newScalar(curve).Pick(random.Stream)
+		text, err := s.(textMarshaler).MarshalText()
+		require.Nil(t, err)
+		parsed, err := ParseScalar(text)
+		require.Nil(t, err)
+		require.True(t, parsed.Equal(s))
+		buff, err := json.Marshal(s)
+		require.Nil(t, err)
+		dec := newScalar(curve)
+		require.Nil(t, json.Unmarshal(buff, dec))
+		require.True(t, dec.Equal(s))
+	}
+}
+
+func TestTextTag(t *testing.T) {
+	p := NewPairingFp254BNb()
+	pt := p.G2().Point().Base()
+	text, err := pt.(textMarshaler).MarshalText()
+	require.Nil(t, err)
+
+	// a point of G2 is not decoded as a point of G1 or as a scalar
+	var derr *DecodeError
+	err = p.G1().Point().(*pointG1).UnmarshalText(text)
+	require.IsType(t, derr, err)
+	require.Equal(t, ErrTextTag, err.(*DecodeError).Reason)
+	_, err = ParseScalar(text)
+	require.Equal(t, ErrTextTag, err.(*DecodeError).Reason)
+
+	for _, bad := range []string{
+		"",
+		"Fp254Nb_G2",
+		"Fp254Nb_G2:zz",
+		"Fp999_G2:00",
+		"Fp254Nb_G3:00",
+		strings.Replace(string(text), "Fp254Nb_G2:", "Fp254Nb_G2:00", 1),
+	} {
+		_, err := ParsePoint([]byte(bad))
+		require.Error(t, err, "%q", bad)
+	}
+}
+
+func TestTextPubPoly(t *testing.T) {
+	g := NewPairingFp254BNb().G2()
+	pri := share.NewPriPoly(g, 3, nil, random.Stream)
+	pub := pri.Commit(nil)
+
+	buff, err := json.Marshal(PubPoly{pub})
+	require.Nil(t, err)
+	var dec PubPoly
+	require.Nil(t, json.Unmarshal(buff, &dec))
+	require.True(t, pub.Equal(dec.PubPoly))
+	require.True(t, pub.Eval(4).V.Equal(dec.Eval(4).V))
+
+	// the points of a polynomial belong to the same group
+	text, err := PubPoly{pub}.MarshalText()
+	require.Nil(t, err)
+	g1, err := NewPairingFp254BNb().G1().Point().Base().(textMarshaler).MarshalText()
+	require.Nil(t, err)
+	require.Error(t, dec.UnmarshalText(append(append(text, ' '), g1...)))
+}
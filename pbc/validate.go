@@ -0,0 +1,160 @@
+package pbc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// Reasons for which a point or a scalar can be rejected when it is decoded.
+// UnmarshalBinary and UnmarshalFrom return them wrapped in a *DecodeError.
+var (
+	ErrInvalidLength = errors.New("invalid length")
+	ErrNotOnCurve    = errors.New("not a point of the curve")
+	ErrNotInSubgroup = errors.New("not in the prime order subgroup")
+	ErrIdentity      = errors.New("identity element not allowed")
+	ErrNonCanonical  = errors.New("non canonical encoding")
+	ErrOutOfRange    = errors.New("scalar out of range")
+)
+
+// DecodeError is the error returned when bytes received from the outside do
+// not decode to a valid element. Reason is one of the Err* variables above.
+type DecodeError struct {
+	Group  string
+	Reason error
+}
+
+func (d *DecodeError) Error() string {
+	return fmt.Sprintf("pbc: decoding %s: %v", d.Group, d.Reason)
+}
+
+// UnmarshalNonIdentity decodes buff into p like UnmarshalBinary does but
+// also rejects the identity element, which is never a valid signature or
+// public key.
+func UnmarshalNonIdentity(p abstract.Point, buff []byte) error {
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return err
+	}
+	var name string
+	var identity bool
+	switch pt := p.(type) {
+	case *pointG1:
+		name = curveName(pt.curve) + "_G1"
+		identity = pt.Equal(newPointG1(pt.base, pt.format).Null())
+	case *pointG2:
+		name = curveName(pt.curve) + "_G2"
+		identity = pt.Equal(newPointG2(pt.base, pt.format).Null())
+	case *pointGT:
+		name = curveName(pt.p.curve) + "_GT"
+		identity = pt.Equal(newPointGT(pt.p).Null())
+	default:
+		return errors.New("pbc: not a pbc point")
+	}
+	if identity {
+		return &DecodeError{Group: name, Reason: ErrIdentity}
+	}
+	return nil
+}
+
+type deserializable interface {
+	serializable
+	Deserialize(buff []byte) error
+}
+
+// deserialize calls the Deserialize method of the backend and checks that
+// the encoding is canonical, i.e. that it is the one the backend would
+// output for the decoded value. Panics of the backend are turned into errors.
+func deserialize(d deserializable, buff []byte) (canonical bool, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			canonical = false
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	if err = d.Deserialize(buff); err != nil {
+		return false, err
+	}
+	return bytes.Equal(d.Serialize(), buff), nil
+}
+
+// g1HasCofactor returns true if the curve has points of G1 outside the
+// prime order subgroup. Barreto-Naehrig curves have a prime order G1, unlike
+// BLS12-381.
+func g1HasCofactor(curve int) bool {
+	return curve == CurveBLS12_381
+}
+
+// g1InSubgroup returns true if r * p is the point at infinity. The
+// multiplication is done with additions only, so the result does not depend
+// on the backend assuming the point is in the subgroup already.
+func g1InSubgroup(p *g1, r *big.Int) bool {
+	acc, zero := *p, *p
+	acc.Clear()
+	zero.Clear()
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		g1Add(&acc, &acc, &acc)
+		if r.Bit(i) == 1 {
+			g1Add(&acc, &acc, p)
+		}
+	}
+	return acc.IsEqual(&zero)
+}
+
+// g2InSubgroup is the G2 version of g1InSubgroup.
+func g2InSubgroup(p *g2, r *big.Int) bool {
+	acc, zero := *p, *p
+	acc.Clear()
+	zero.Clear()
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		g2Add(&acc, &acc, &acc)
+		if r.Bit(i) == 1 {
+			g2Add(&acc, &acc, p)
+		}
+	}
+	return acc.IsEqual(&zero)
+}
+
+// gtInSubgroup returns true if x^r = 1.
+func gtInSubgroup(x *gt, r *big.Int) bool {
+	acc, one := *x, *x
+	one.SetInt64(1)
+	acc.SetInt64(1)
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		gtMul(&acc, &acc, &acc)
+		if r.Bit(i) == 1 {
+			gtMul(&acc, &acc, x)
+		}
+	}
+	return acc.IsEqual(&one)
+}
+
+var orders = struct {
+	sync.Mutex
+	m map[int]*big.Int
+}{m: make(map[int]*big.Int)}
+
+// curveOrder returns the order r of the groups of the curve, computed from
+// the encoding of r - 1 given by the backend. It must not be called while
+// holding the curve.
+func curveOrder(curve int) *big.Int {
+	orders.Lock()
+	defer orders.Unlock()
+	if r, ok := orders.m[curve]; ok {
+		return r
+	}
+	var buff []byte
+	func() {
+		defer withCurve(curve)()
+		minusOne := newFr(curve)
+		minusOne.SetInt64(-1)
+		buff = minusOne.Serialize()
+	}()
+	r := getLittleEndian(buff)
+	r.Add(r, big.NewInt(1))
+	orders.m[curve] = r
+	return r
+}
@@ -0,0 +1,158 @@
+package pbc
+
+import (
+	"math/big"
+	"testing"
+
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireReason(t *testing.T, err error, reasons ...error) {
+	require.NotNil(t, err)
+	d, ok := err.(*DecodeError)
+	require.True(t, ok, "not a DecodeError: %v", err)
+	require.Contains(t, reasons, d.Reason)
+}
+
+func TestDecodeLength(t *testing.T) {
+	p := NewPairingFp254BNb()
+	points := []abstract.Point{p.G1().Point(), p.G2().Point(), p.GT().Point()}
+	for _, pt := range points {
+		requireReason(t, pt.UnmarshalBinary(nil), ErrInvalidLength)
+		requireReason(t, pt.UnmarshalBinary([]byte{}), ErrInvalidLength)
+		requireReason(t, pt.UnmarshalBinary(make([]byte, pt.MarshalSize()-1)), ErrInvalidLength)
+	}
+	requireReason(t, p.G1().Scalar().UnmarshalBinary(nil), ErrInvalidLength)
+	requireReason(t, p.G1().Scalar().UnmarshalBinary(make([]byte, 31)), ErrInvalidLength)
+}
+
+func TestDecodeG1(t *testing.T) {
+	p := NewPairingFp254BNb()
+	size := p.G1().PointLen()
+
+	// about half of the x coordinates are not on the curve
+	var rejected int
+	for i := 0; i < 32; i++ {
+		buff := random.Bytes(size, random.Stream)
+		buff[size-1] &= 0x1f
+		var q g1
+		if q.Deserialize(buff) == nil {
+			continue
+		}
+		rejected++
+		pt := p.G1().Point().Base()
+		requireReason(t, pt.UnmarshalBinary(buff), ErrNotOnCurve)
+		require.True(t, pt.Equal(p.G1().Point().Base()))
+	}
+	require.NotZero(t, rejected)
+
+	// x + p instead of x
+	pt := p.G1().Point().Mul(nil, p.G1().Scalar().Pick(random.Stream))
+	buff, err := pt.MarshalBinary()
+	require.Nil(t, err)
+	flag := buff[size-1] & 0x80
+	buff[size-1] &= 0x7f
+	x := getLittleEndian(buff)
+	x.Add(x, fieldModulus(t))
+	putLittleEndian(buff, x)
+	buff[size-1] |= flag
+	requireReason(t, p.G1().Point().UnmarshalBinary(buff), ErrNotOnCurve, ErrNonCanonical)
+}
+
+func TestDecodeG2(t *testing.T) {
+	p := NewPairingFp254BNb()
+	size := p.G2().PointLen()
+
+	// points of the twist are almost never in the subgroup
+	var found bool
+	for i := 0; i < 64 && !found; i++ {
+		buff := random.Bytes(size, random.Stream)
+		buff[size/2-1] &= 0x1f
+		buff[size-1] &= 0x1f
+		var q g2
+		if q.Deserialize(buff) != nil {
+			requireReason(t, p.G2().Point().UnmarshalBinary(buff), ErrNotOnCurve)
+			continue
+		}
+		found = true
+		requireReason(t, p.G2().Point().UnmarshalBinary(buff), ErrNotInSubgroup)
+
+		u := NewPairingFp254BNb()
+		u.SetPointFormat(Uncompressed)
+		pt := &pointG2{g: q, curve: CurveFp254BNb, format: Uncompressed}
+		ubuff, err := pt.MarshalBinary()
+		require.Nil(t, err)
+		requireReason(t, u.G2().Point().UnmarshalBinary(ubuff), ErrNotInSubgroup)
+	}
+	require.True(t, found)
+
+	pt := p.G2().Point().Mul(nil, p.G2().Scalar().Pick(random.Stream))
+	buff, err := pt.MarshalBinary()
+	require.Nil(t, err)
+	require.Nil(t, p.G2().Point().UnmarshalBinary(buff))
+}
+
+func TestDecodeGT(t *testing.T) {
+	p := NewPairingFp254BNb()
+	e := p.GT().Point().Base()
+	buff, err := e.MarshalBinary()
+	require.Nil(t, err)
+	require.Nil(t, p.GT().Point().UnmarshalBinary(buff))
+
+	buff[0]++
+	requireReason(t, p.GT().Point().UnmarshalBinary(buff), ErrNotInSubgroup)
+
+	for i := range buff {
+		buff[i] = 0xff
+	}
+	requireReason(t, p.GT().Point().UnmarshalBinary(buff), ErrNotOnCurve, ErrNonCanonical)
+	requireReason(t, p.GT().Point().UnmarshalBinary(make([]byte, len(buff))), ErrNotInSubgroup)
+}
+
+func TestDecodeScalar(t *testing.T) {
+	p := NewPairingFp254BNb()
+	r := curveOrder(p.Curve())
+	buff := make([]byte, p.G1().ScalarLen())
+	for _, v := range []*big.Int{r, new(big.Int).Add(r, big.NewInt(1))} {
+		putLittleEndian(buff, v)
+		s := p.G1().Scalar().SetInt64(42)
+		requireReason(t, s.UnmarshalBinary(buff), ErrOutOfRange)
+		require.True(t, s.Equal(p.G1().Scalar().SetInt64(42)))
+	}
+	putLittleEndian(buff, new(big.Int).Sub(r, big.NewInt(1)))
+	s := p.G1().Scalar()
+	require.Nil(t, s.UnmarshalBinary(buff))
+	require.True(t, s.Equal(p.G1().Scalar().SetInt64(-1)))
+}
+
+func TestUnmarshalNonIdentity(t *testing.T) {
+	p := NewPairingFp254BNb()
+	for _, g := range []abstract.Group{p.G1(), p.G2(), p.GT()} {
+		buff, err := g.Point().Null().MarshalBinary()
+		require.Nil(t, err)
+		require.Nil(t, g.Point().UnmarshalBinary(buff))
+		requireReason(t, UnmarshalNonIdentity(g.Point(), buff), ErrIdentity)
+
+		buff, err = g.Point().Base().MarshalBinary()
+		require.Nil(t, err)
+		require.Nil(t, UnmarshalNonIdentity(g.Point(), buff))
+	}
+}
+
+// fieldModulus returns the characteristic of the base field as the sum of the
+// y coordinates of a point and its opposite.
+func fieldModulus(t *testing.T) *big.Int {
+	u := NewPairingFp254BNb()
+	u.SetPointFormat(Uncompressed)
+	b := u.G1().Point().Base()
+	buff1, err := b.MarshalBinary()
+	require.Nil(t, err)
+	buff2, err := u.G1().Point().Neg(b).MarshalBinary()
+	require.Nil(t, err)
+	size := len(buff1) / 2
+	y := getLittleEndian(buff1[size:])
+	return y.Add(y, getLittleEndian(buff2[size:]))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dedis/paper_17_dfinity/pbc"
 	"github.com/dedis/paper_17_dfinity/pedersen/vss"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/share"
@@ -28,6 +29,14 @@ func (d *DistKeyShare) Polynomial() *share.PubPoly {
 	return d.Poly
 }
 
+// Destroy wipes the share of the distributed secret once it is not needed
+// anymore. The public polynomial is left untouched.
+func (d *DistKeyShare) Destroy() {
+	if d.Share != nil {
+		pbc.DestroyScalar(d.Share.V)
+	}
+}
+
 // Deal holds the Deal for one participant as well as the index of the issuing
 // Dealer.
 //  NOTE: Doing that in vss.go would be possible but then the Dealer is always
@@ -78,7 +87,7 @@ type DistKeyGenerator struct {
 // threshold t parameter. It returns an error if the secret key's commitment
 // can't be found in the list of participants.
 func NewDistKeyGenerator(suite abstract.Suite, longterm abstract.Scalar, participants []abstract.Point, r cipher.Stream, t int) (*DistKeyGenerator, error) {
-	pub := suite.Point().Mul(nil, longterm)
+	pub := secretPoint(suite).Mul(nil, longterm)
 	// find our index
 	var found bool
 	var index uint32
@@ -95,6 +104,8 @@ func NewDistKeyGenerator(suite abstract.Suite, longterm abstract.Scalar, partici
 	var err error
 	// generate our dealer / deal
 	ownSec := suite.Scalar().Pick(r)
+	// the dealer works on its own copy
+	defer pbc.DestroyScalar(ownSec)
 	dealer, err := vss.NewDealer(suite, longterm, ownSec, participants, r, t)
 	if err != nil {
 		return nil, err
@@ -124,6 +135,9 @@ func NewDistKeyGenerator(suite abstract.Suite, longterm abstract.Scalar, partici
 //
 // This method panics if it can't process its own deal.
 func (d *DistKeyGenerator) Deals() (map[int]*Deal, error) {
+	if d.dealer == nil {
+		return nil, errDestroyed
+	}
 	deals, err := d.dealer.EncryptedDeals()
 	if err != nil {
 		return nil, err
@@ -309,9 +323,12 @@ func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
 		return nil, err
 	}
 
-	if !pub.Check(&share.PriShare{I: int(d.index), V: sh}) {
+	pubShare := pbc.EvalPubPoly(d.suite, pub, int(d.index))
+	check := secretPoint(d.suite).Mul(nil, sh)
+	if !pubShare.V.Equal(check) {
 		panic("aie")
 	}
+	pbc.Release(d.suite, pubShare.V, check)
 
 	return &DistKeyShare{
 		Poly: pub,
@@ -322,6 +339,35 @@ func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
 	}, nil
 }
 
+// Destroy wipes the secret shared by this participant and the shares it
+// received from the others, as soon as the distributed key share has been
+// computed or the protocol run is abandoned. The DistKeyShare returned before
+// is independent and must be destroyed separately. Afterwards the generator
+// is not certified anymore and returns errors.
+func (d *DistKeyGenerator) Destroy() {
+	if d.dealer != nil {
+		d.dealer.Destroy()
+		d.dealer = nil
+	}
+	for _, v := range d.verifiers {
+		v.Destroy()
+	}
+	d.verifiers = make(map[uint32]*vss.Verifier)
+}
+
+var errDestroyed = errors.New("dkg: generator destroyed")
+
+// secretPoint returns a new point of the suite which multiplies in constant
+// time when the suite supports it, for the multiplications by the longterm
+// key and the distributed share.
+func secretPoint(suite abstract.Suite) abstract.Point {
+	p := suite.Point()
+	if v, ok := p.(pbc.VarTimer); ok {
+		v.SetVarTime(false)
+	}
+	return p
+}
+
 func findPub(list []abstract.Point, i uint32) (abstract.Point, bool) {
 	if i >= uint32(len(list)) {
 		return nil, false
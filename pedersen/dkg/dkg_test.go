@@ -521,6 +521,22 @@ func TestDistKeyShare(t *testing.T) {
 	commitSecret := suite.Point().Mul(nil, secret)
 	public := dkss[0].Polynomial().Commit()
 	assert.Equal(t, public.String(), commitSecret.String())
+
+	// the shares survive the destruction of the generators
+	for _, dkg := range dkgs {
+		dkg.Destroy()
+		assert.False(t, dkg.Certified())
+		_, err := dkg.DistKeyShare()
+		assert.NotNil(t, err)
+		_, err = dkg.Deals()
+		assert.Equal(t, errDestroyed, err)
+	}
+	secret2, err := share.RecoverSecret(suite, shares, nbParticipants, nbParticipants)
+	require.Nil(t, err)
+	assert.True(t, secret.Equal(secret2))
+
+	dkss[0].Destroy()
+	assert.True(t, dkss[0].Share.V.Equal(suite.Scalar().Zero()))
 }
 
 func dkgGen() []*DistKeyGenerator {
@@ -549,7 +565,7 @@ func checkDks(dks1, dks2 *DistKeyShare) bool {
 	return dks1.Polynomial().Equal(dks2.Polynomial())
 }
 
-func fullExchange(t *testing.T) {
+func fullExchange(t testing.TB) {
 	dkgs = dkgGen()
 	// full secret sharing exchange
 	// 1. broadcast deals
@@ -585,3 +601,20 @@ func fullExchange(t *testing.T) {
 	}
 
 }
+
+func BenchmarkDKGFullExchange(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		fullExchange(b)
+	}
+}
+
+func BenchmarkDistKeyShare(b *testing.B) {
+	fullExchange(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dkgs[0].DistKeyShare(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
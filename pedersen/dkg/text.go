@@ -0,0 +1,91 @@
+package dkg
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// distKeyShareJSON is the JSON form of a DistKeyShare, with the points and
+// scalars in the text form of the pbc package.
+type distKeyShareJSON struct {
+	Poly  pbc.PubPoly
+	Index int
+	Share string
+}
+
+// MarshalJSON returns the public polynomial, the index and the share of d as
+// a JSON object. Note that it contains the share of the distributed secret.
+func (d *DistKeyShare) MarshalJSON() ([]byte, error) {
+	v, err := d.Share.V.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&distKeyShareJSON{
+		Poly:  pbc.PubPoly{PubPoly: d.Poly},
+		Index: d.Share.I,
+		Share: string(v),
+	})
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON. The curve and group are
+// given by the encoding.
+func (d *DistKeyShare) UnmarshalJSON(buff []byte) error {
+	var dj distKeyShareJSON
+	if err := json.Unmarshal(buff, &dj); err != nil {
+		return err
+	}
+	if dj.Poly.PubPoly == nil {
+		return errors.New("dkg: missing public polynomial")
+	}
+	v, err := pbc.ParseScalar([]byte(dj.Share))
+	if err != nil {
+		return err
+	}
+	d.Poly = dj.Poly.PubPoly
+	d.Share = &share.PriShare{I: dj.Index, V: v}
+	return nil
+}
+
+// MarshalText returns the index and the share of d followed by the public
+// polynomial, separated by spaces, so a DistKeyShare can be stored as a
+// single string in a TOML configuration file.
+func (d *DistKeyShare) MarshalText() ([]byte, error) {
+	v, err := d.Share.V.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	poly, err := pbc.PubPoly{PubPoly: d.Poly}.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strconv.Itoa(d.Share.I) + " " + string(v) + " " + string(poly)), nil
+}
+
+// UnmarshalText decodes the output of MarshalText.
+func (d *DistKeyShare) UnmarshalText(text []byte) error {
+	fields := strings.SplitN(string(text), " ", 3)
+	if len(fields) != 3 {
+		return errors.New("dkg: invalid text encoding of a DistKeyShare")
+	}
+	i, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return err
+	}
+	v, err := pbc.ParseScalar([]byte(fields[1]))
+	if err != nil {
+		return err
+	}
+	var poly pbc.PubPoly
+	if err := poly.UnmarshalText([]byte(fields[2])); err != nil {
+		return err
+	}
+	d.Poly = poly.PubPoly
+	d.Share = &share.PriShare{I: i, V: v}
+	return nil
+}
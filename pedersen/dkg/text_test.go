@@ -0,0 +1,33 @@
+package dkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+func TestDistKeyShareText(t *testing.T) {
+	pri := share.NewPriPoly(suite, 3, nil, random.Stream)
+	dks := &DistKeyShare{Poly: pri.Commit(nil), Share: pri.Eval(2)}
+
+	buff, err := json.Marshal(dks)
+	require.Nil(t, err)
+	dec := new(DistKeyShare)
+	require.Nil(t, json.Unmarshal(buff, dec))
+	require.True(t, checkDks(dks, dec))
+	require.Equal(t, dks.Share.I, dec.Share.I)
+	require.True(t, dks.Share.V.Equal(dec.Share.V))
+	require.True(t, dec.Polynomial().Check(dec.PriShare()))
+
+	text, err := dks.MarshalText()
+	require.Nil(t, err)
+	dec = new(DistKeyShare)
+	require.Nil(t, dec.UnmarshalText(text))
+	require.True(t, checkDks(dks, dec))
+	require.True(t, dks.Share.V.Equal(dec.Share.V))
+
+	require.Error(t, dec.UnmarshalText([]byte("2 garbage")))
+}
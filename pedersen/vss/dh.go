@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	"hash"
 
+	"github.com/dedis/paper_17_dfinity/pbc"
 	"gopkg.in/dedis/crypto.v0/abstract"
 
 	"golang.org/x/crypto/hkdf"
@@ -12,19 +13,32 @@ import (
 
 // dhExchange computes the shared key from a private key and a public key
 func dhExchange(suite abstract.Suite, ownPrivate abstract.Scalar, remotePublic abstract.Point) abstract.Point {
-	sk := suite.Point()
+	sk := secretPoint(suite)
 	sk.Mul(remotePublic, ownPrivate)
 	return sk
 }
 
+// secretPoint returns a new point of the suite which multiplies in constant
+// time when the suite supports it, for the multiplications by private keys,
+// secrets and shares.
+func secretPoint(suite abstract.Suite) abstract.Point {
+	p := suite.Point()
+	if v, ok := p.(pbc.VarTimer); ok {
+		v.SetVarTime(false)
+	}
+	return p
+}
+
 var sharedKeyLength = 32
 
 // newAEAD returns the AEAD cipher to be use to encrypt a share
 func newAEAD(fn func() hash.Hash, preSharedKey abstract.Point, context []byte) (cipher.AEAD, error) {
 	preBuff, _ := preSharedKey.MarshalBinary()
 	reader := hkdf.New(fn, preBuff, nil, context)
+	pbc.Zeroize(preBuff)
 
 	sharedKey := make([]byte, sharedKeyLength)
+	defer pbc.Zeroize(sharedKey)
 	if _, err := reader.Read(sharedKey); err != nil {
 		return nil, err
 	}
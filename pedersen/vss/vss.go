@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"github.com/dedis/paper_17_dfinity/poly"
 	"github.com/dedis/protobuf"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/random"
@@ -106,12 +108,13 @@ type Justification struct {
 // the number of shares required to reconstruct the secret. It is HIGHLY
 // RECOMMENDED to use a threshold higher or equal than what the method
 // MinimumT() returns, otherwise it breaks the security assumptions of the whole
-// scheme. It returns an error if the t is inferior or equal to 2.
+// scheme. It returns an error if the t is inferior or equal to 2. The dealer
+// keeps its own copy of secret, which Destroy wipes.
 func NewDealer(suite abstract.Suite, longterm, secret abstract.Scalar, verifiers []abstract.Point, r cipher.Stream, t int) (*Dealer, error) {
 	d := &Dealer{
 		suite:     suite,
 		long:      longterm,
-		secret:    secret,
+		secret:    secret.Clone(),
 		verifiers: verifiers,
 	}
 	if !validT(t, verifiers) {
@@ -119,10 +122,11 @@ func NewDealer(suite abstract.Suite, longterm, secret abstract.Scalar, verifiers
 	}
 	d.t = t
 
-	f := share.NewPriPoly(d.suite, d.t, d.secret, r)
-	d.pub = d.suite.Point().Mul(nil, d.long)
+	f := poly.NewRandom(d.suite, d.t, d.secret, r)
+	defer f.Destroy()
+	d.pub = secretPoint(d.suite).Mul(nil, d.long)
 
-	// Compute public polynomial coefficients
+	// Compute public polynomial coefficients, in constant time
 	F := f.Commit(d.suite.Point().Base())
 	_, d.secretCommits = F.Info()
 
@@ -136,7 +140,7 @@ func NewDealer(suite abstract.Suite, longterm, secret abstract.Scalar, verifiers
 	// C = F + G
 	d.deals = make([]*Deal, len(d.verifiers))
 	for i := range d.verifiers {
-		fi := f.Eval(i)
+		fi := f.Share(i)
 		d.deals[i] = &Deal{
 			SessionID:   d.sessionID,
 			SecShare:    fi,
@@ -150,6 +154,9 @@ func NewDealer(suite abstract.Suite, longterm, secret abstract.Scalar, verifiers
 
 // PlaintextDeal returns the plaintext version of the deal destined for peer i.
 func (d *Dealer) PlaintextDeal(i int) (*Deal, error) {
+	if d.destroyed() {
+		return nil, errDealerDestroyed
+	}
 	if i >= len(d.deals) {
 		return nil, errors.New("dealer: PlaintextDeal given wrong index")
 	}
@@ -168,9 +175,13 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 	if !ok {
 		return nil, errors.New("dealer: wrong index to generate encrypted deal")
 	}
+	if d.destroyed() {
+		return nil, errDealerDestroyed
+	}
 	// gen ephemeral key
 	dhSecret := d.suite.Scalar().Pick(random.Stream)
-	dhPublic := d.suite.Point().Mul(nil, dhSecret)
+	defer pbc.DestroyScalar(dhSecret)
+	dhPublic := secretPoint(d.suite).Mul(nil, dhSecret)
 	// signs the public key
 	dhPublicBuff, _ := dhPublic.MarshalBinary()
 	signature, err := sign.Schnorr(d.suite, d.long, dhPublicBuff)
@@ -190,6 +201,7 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 		return nil, err
 	}
 	encrypted := gcm.Seal(nil, nonce, dealBuff, d.hkdfContext)
+	pbc.Zeroize(dealBuff)
 	return &EncryptedDeal{
 		DHKey:     dhPublic,
 		Signature: signature,
@@ -218,6 +230,9 @@ func (d *Dealer) EncryptedDeals() ([]*EncryptedDeal, error) {
 // participants. If it's an invalid complaint, it returns an error about the
 // complaint. The verifiers will also ignore an invalid Complaint.
 func (d *Dealer) ProcessResponse(r *Response) (*Justification, error) {
+	if d.destroyed() {
+		return nil, errDealerDestroyed
+	}
 	if err := d.verifyResponse(r); err != nil {
 		return nil, err
 	}
@@ -241,34 +256,64 @@ func (d *Dealer) ProcessResponse(r *Response) (*Justification, error) {
 
 // SecretCommit returns the commitment of the secret being shared by this
 // dealer. This function is only to be called once the deal has enough approvals
-// and is verified otherwise it returns nil.
+// and is verified otherwise it returns nil, as it does once the dealer is
+// destroyed.
 func (d *Dealer) SecretCommit() abstract.Point {
-	if !d.EnoughApprovals() || !d.DealCertified() {
+	if d.destroyed() || !d.EnoughApprovals() || !d.DealCertified() {
 		return nil
 	}
-	return d.suite.Point().Mul(nil, d.secret)
+	return secretPoint(d.suite).Mul(nil, d.secret)
 }
 
 // Commits returns the commitments of the coefficient of the secret polynomial
-// the Dealer is sharing.
+// the Dealer is sharing, or nil if the deal is not certified or the dealer is
+// destroyed.
 func (d *Dealer) Commits() []abstract.Point {
-	if !d.EnoughApprovals() || !d.DealCertified() {
+	if d.destroyed() || !d.EnoughApprovals() || !d.DealCertified() {
 		return nil
 	}
 	return d.secretCommits
 }
 
-// Key returns the longterm key pair used by this Dealer.
+// Key returns the longterm key pair used by this Dealer, or nil once it is
+// destroyed.
 func (d *Dealer) Key() (abstract.Scalar, abstract.Point) {
+	if d.destroyed() {
+		return nil, nil
+	}
 	return d.long, d.pub
 }
 
 // SessionID returns the current sessionID generated by this dealer for this
-// protocol run.
+// protocol run, or nil once it is destroyed.
 func (d *Dealer) SessionID() []byte {
+	if d.destroyed() {
+		return nil
+	}
 	return d.sessionID
 }
 
+// Destroy wipes the copy of the secret being shared and the shares of all the
+// deals, once the dealer is not needed anymore. The longterm key belongs to
+// the caller so it is only released. Afterwards, the methods returning deals
+// and justifications return errDealerDestroyed and the accessors nil.
+func (d *Dealer) Destroy() {
+	if d.destroyed() {
+		return
+	}
+	pbc.DestroyScalar(d.secret)
+	for _, deal := range d.deals {
+		deal.destroy()
+	}
+	d.secret = nil
+	d.long = nil
+	d.deals = nil
+}
+
+func (d *Dealer) destroyed() bool {
+	return d.deals == nil
+}
+
 // Verifier receives a Deal from a Dealer, can reply with a Complaint, and can
 // collaborate with other Verifiers to reconstruct a secret.
 type Verifier struct {
@@ -293,7 +338,7 @@ type Verifier struct {
 func NewVerifier(suite abstract.Suite, longterm abstract.Scalar, dealerKey abstract.Point,
 	verifiers []abstract.Point) (*Verifier, error) {
 
-	pub := suite.Point().Mul(nil, longterm)
+	pub := secretPoint(suite).Mul(nil, longterm)
 	var ok bool
 	var index int
 	for i, v := range verifiers {
@@ -390,9 +435,15 @@ func (v *Verifier) decryptDeal(e *EncryptedDeal) (*Deal, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the plaintext holds the share, wipe it once decoded
+	defer pbc.Zeroize(decrypted)
 	deal := &Deal{}
-	err = deal.UnmarshalBinary(v.suite, decrypted)
-	return deal, err
+	if err := deal.UnmarshalBinary(v.suite, decrypted); err != nil {
+		return nil, err
+	}
+	// the decoder may alias the byte slices into the wiped buffer
+	deal.SessionID = append([]byte(nil), deal.SessionID...)
+	return deal, nil
 }
 
 // ProcessResponse analyzes the given response. If it's a valid complaint, the
@@ -438,6 +489,17 @@ func (v *Verifier) SessionID() []byte {
 	return v.sid
 }
 
+// Destroy wipes the share received from the Dealer, once the verifier is not
+// needed anymore. The longterm key belongs to the caller so it is only
+// released.
+func (v *Verifier) Destroy() {
+	if v.aggregator != nil {
+		v.deal.destroy()
+		v.deal = nil
+	}
+	v.longterm = nil
+}
+
 // RecoverSecret recovers the secret shared by a Dealer by gathering at least t
 // Deals from the verifiers. It returns an error if there is not enough Deals or
 // if all Deals don't have the same SessionID.
@@ -451,7 +513,7 @@ func RecoverSecret(suite abstract.Suite, deals []*Deal, n, t int) (abstract.Scal
 			return nil, errors.New("vss: all deals need to have same session id")
 		}
 	}
-	return share.RecoverSecret(suite, shares, t, n)
+	return poly.RecoverSecret(suite, shares, t, n)
 }
 
 // aggregator is used to collect all deals, and responses for one protocol run.
@@ -484,6 +546,8 @@ func newAggregator(suite abstract.Suite, dealer abstract.Point, verifiers, commi
 
 var errDealAlreadyProcessed = errors.New("vss: verifier already received a deal")
 
+var errDealerDestroyed = errors.New("vss: dealer destroyed")
+
 // VerifyDeal analyzes the deal and returns an error if it's incorrect. If
 // inclusion is true, it also returns an error if it the second time this struct
 // analyzes a Deal.
@@ -511,11 +575,11 @@ func (a *aggregator) VerifyDeal(d *Deal, inclusion bool) error {
 		return errors.New("vss: index out of bounds in Deal")
 	}
 	// compute fi * G
-	fig := a.suite.Point().Base().Mul(nil, fi.V)
+	fig := secretPoint(a.suite).Mul(nil, fi.V)
 
 	commitPoly := share.NewPubPoly(a.suite, nil, d.Commitments)
 
-	pubShare := commitPoly.Eval(fi.I)
+	pubShare := pbc.EvalPubPoly(a.suite, commitPoly, fi.I)
 	if !fig.Equal(pubShare.V) {
 		return errors.New("vss: share does not verify against commitments in Deal")
 	}
@@ -668,12 +732,29 @@ func (d *Deal) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary reads the Deal from the binary represenstation.
 func (d *Deal) UnmarshalBinary(s abstract.Suite, buff []byte) error {
-	constructors := make(protobuf.Constructors)
-	var point abstract.Point
-	var secret abstract.Scalar
-	constructors[reflect.TypeOf(&point).Elem()] = func() interface{} { return s.Point() }
-	constructors[reflect.TypeOf(&secret).Elem()] = func() interface{} { return s.Scalar() }
-	return protobuf.DecodeWithConstructors(buff, d, constructors)
+	return protobuf.DecodeWithConstructors(buff, d, constructors(s))
+}
+
+// destroy wipes the share of the deal. It does nothing on a nil deal.
+func (d *Deal) destroy() {
+	if d == nil || d.SecShare == nil {
+		return
+	}
+	pbc.DestroyScalar(d.SecShare.V)
+}
+
+var pointType = reflect.TypeOf((*abstract.Point)(nil)).Elem()
+var scalarType = reflect.TypeOf((*abstract.Scalar)(nil)).Elem()
+
+// constructors returns the protobuf constructors for points and scalars, which
+// are instantiated through the suite's New method.
+func constructors(s abstract.Suite) protobuf.Constructors {
+	cons := make(protobuf.Constructors)
+	for _, t := range []reflect.Type{pointType, scalarType} {
+		t := t
+		cons[t] = func() interface{} { return s.New(t) }
+	}
+	return cons
 }
 
 // Hash returns the hash of a Justification.
@@ -79,6 +79,49 @@ func TestVSSWhole(t *testing.T) {
 	assert.Equal(t, dealer.secret.String(), sec.String())
 }
 
+func TestVSSDestroy(t *testing.T) {
+	sec := suite.Scalar().Pick(reader)
+	dealer, err := NewDealer(suite, dealerSec, sec, verifiersPub, reader, vssThreshold)
+	require.Nil(t, err)
+	v, err := NewVerifier(suite, verifiersSec[0], dealerPub, verifiersPub)
+	require.Nil(t, err)
+	encD, err := dealer.EncryptedDeal(0)
+	require.Nil(t, err)
+	_, err = v.ProcessEncryptedDeal(encD)
+	require.Nil(t, err)
+	received := v.deal.SecShare.V
+	dealt := dealer.deals[0].SecShare.V
+	require.False(t, received.Equal(suite.Scalar().Zero()))
+
+	copied := dealer.secret
+	dealer.Destroy()
+	require.True(t, copied.Equal(suite.Scalar().Zero()))
+	require.True(t, dealt.Equal(suite.Scalar().Zero()))
+	_, err = dealer.EncryptedDeal(0)
+	require.Equal(t, errDealerDestroyed, err)
+	_, err = dealer.EncryptedDeals()
+	require.Equal(t, errDealerDestroyed, err)
+	_, err = dealer.PlaintextDeal(0)
+	require.Equal(t, errDealerDestroyed, err)
+	_, err = dealer.ProcessResponse(&Response{})
+	require.Equal(t, errDealerDestroyed, err)
+	require.Nil(t, dealer.SecretCommit())
+	require.Nil(t, dealer.Commits())
+	require.Nil(t, dealer.SessionID())
+	long, pub := dealer.Key()
+	require.Nil(t, long)
+	require.Nil(t, pub)
+	dealer.Destroy()
+	// the secret and the longterm key of the caller are left alone
+	require.False(t, sec.Equal(suite.Scalar().Zero()))
+	require.False(t, dealerSec.Equal(suite.Scalar().Zero()))
+
+	v.Destroy()
+	require.True(t, received.Equal(suite.Scalar().Zero()))
+	require.Nil(t, v.Deal())
+	require.False(t, verifiersSec[0].Equal(suite.Scalar().Zero()))
+}
+
 /*func TestVSSDealerNew(t *testing.T) {*/
 //goodT := MinimumT(nbVerifiers)
 //_, err := NewDealer(suite, dealerSec, secret, verifiersPub, reader, goodT)
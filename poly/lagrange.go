@@ -0,0 +1,311 @@
+package poly
+
+import (
+	"errors"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// Errors returned by the interpolation functions.
+var (
+	ErrDuplicatePoint   = errors.New("poly: duplicate interpolation point")
+	ErrInverseOfZero    = errors.New("poly: inverse of zero")
+	ErrNotEnoughShares  = errors.New("poly: not enough good shares")
+	errLengthsDifferent = errors.New("poly: interpolation points and values of different lengths")
+)
+
+// BatchInvert replaces each scalar of xs by its inverse with a single
+// inversion and 3(n - 1) multiplications, using Montgomery's trick. The
+// scalars are left unchanged if one of them is zero.
+func BatchInvert(g abstract.Group, xs []abstract.Scalar) error {
+	if len(xs) == 0 {
+		return nil
+	}
+	zero := g.Scalar().Zero()
+	prefix := make([]abstract.Scalar, len(xs))
+	acc := g.Scalar().One()
+	for i, x := range xs {
+		if x.Equal(zero) {
+			return ErrInverseOfZero
+		}
+		prefix[i] = acc.Clone()
+		acc.Mul(acc, x)
+	}
+	acc.Inv(acc)
+	t := g.Scalar()
+	for i := len(xs) - 1; i >= 0; i-- {
+		// acc = 1 / (x_0 ... x_i)
+		t.Mul(acc, prefix[i])
+		acc.Mul(acc, xs[i])
+		xs[i].Set(t)
+	}
+	return nil
+}
+
+// LagrangeCoefficients returns the Lagrange basis polynomials of the points
+// xs evaluated at x,
+//
+//	l_i(x) = prod_{j != i} (x - x_j) / (x_i - x_j)
+//
+// so that f(x) = sum_i l_i(x) f(x_i) for any f of degree lower than len(xs).
+// All the coefficients are computed with a single inversion.
+func LagrangeCoefficients(g abstract.Group, xs []abstract.Scalar, x abstract.Scalar) ([]abstract.Scalar, error) {
+	if err := checkDistinct(xs); err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	coeffs := make([]abstract.Scalar, n)
+	diff := make([]abstract.Scalar, n)
+	zero := g.Scalar().Zero()
+	for i, xi := range xs {
+		diff[i] = g.Scalar().Sub(x, xi)
+		if diff[i].Equal(zero) {
+			// x is one of the points: l_i(x) is 1 for it and 0 for the others
+			for j := range coeffs {
+				coeffs[j] = g.Scalar().Zero()
+			}
+			coeffs[i].One()
+			return coeffs, nil
+		}
+	}
+	num := g.Scalar().One()
+	for _, d := range diff {
+		num.Mul(num, d)
+	}
+	// l_i(x) = num / ((x - x_i) prod_{j != i} (x_i - x_j))
+	t := g.Scalar()
+	for i, xi := range xs {
+		den := diff[i].Clone()
+		for j, xj := range xs {
+			if i != j {
+				den.Mul(den, t.Sub(xi, xj))
+			}
+		}
+		coeffs[i] = den
+	}
+	if err := BatchInvert(g, coeffs); err != nil {
+		return nil, err
+	}
+	for _, c := range coeffs {
+		c.Mul(c, num)
+	}
+	return coeffs, nil
+}
+
+// LagrangeAtZero returns the Lagrange coefficients at 0 of the shares of the
+// given indices, whose points are index + 1 as in the share package, with
+// which the secret is the linear combination of the shares.
+func LagrangeAtZero(g abstract.Group, indices []int) ([]abstract.Scalar, error) {
+	return LagrangeCoefficients(g, indexPoints(g, indices), g.Scalar().Zero())
+}
+
+// Interpolate returns the polynomial of degree lower than len(xs) taking the
+// values ys at the points xs.
+func Interpolate(g abstract.Group, xs, ys []abstract.Scalar) (*Poly, error) {
+	if len(xs) != len(ys) {
+		return nil, errLengthsDifferent
+	}
+	basis, err := lagrangeBasis(g, xs)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]abstract.Scalar, len(xs))
+	t := g.Scalar()
+	for k := range coeffs {
+		coeffs[k] = g.Scalar().Zero()
+		for i, y := range ys {
+			coeffs[k].Add(coeffs[k], t.Mul(basis[i][k], y))
+		}
+	}
+	return (&Poly{g: g, coeffs: coeffs}).trim(), nil
+}
+
+// lagrangeBasis returns the coefficients of the Lagrange basis polynomials
+// of the points xs. They are the quotients of M(X) = prod_j (X - x_j) by
+// X - x_i, computed by synthetic division, divided by prod_{j != i} (x_i -
+// x_j).
+func lagrangeBasis(g abstract.Group, xs []abstract.Scalar) ([][]abstract.Scalar, error) {
+	if err := checkDistinct(xs); err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	if n == 0 {
+		return nil, nil
+	}
+	// m holds the coefficients of M, the constant one first
+	m := make([]abstract.Scalar, n+1)
+	for i := range m {
+		m[i] = g.Scalar().Zero()
+	}
+	m[0].One()
+	t := g.Scalar()
+	for j, xj := range xs {
+		for k := j + 1; k > 0; k-- {
+			m[k].Sub(m[k-1], t.Mul(m[k], xj))
+		}
+		m[0].Mul(m[0], t.Neg(xj))
+	}
+
+	weights := make([]abstract.Scalar, n)
+	for i, xi := range xs {
+		weights[i] = g.Scalar().One()
+		for j, xj := range xs {
+			if i != j {
+				weights[i].Mul(weights[i], t.Sub(xi, xj))
+			}
+		}
+	}
+	if err := BatchInvert(g, weights); err != nil {
+		return nil, err
+	}
+
+	basis := make([][]abstract.Scalar, n)
+	for i, xi := range xs {
+		q := make([]abstract.Scalar, n)
+		q[n-1] = m[n].Clone()
+		for k := n - 1; k > 0; k-- {
+			q[k-1] = g.Scalar().Add(m[k], t.Mul(xi, q[k]))
+		}
+		for _, c := range q {
+			c.Mul(c, weights[i])
+		}
+		basis[i] = q
+	}
+	return basis, nil
+}
+
+// RecoverSecret recovers the secret from t of the shares, like
+// share.RecoverSecret, with the Lagrange coefficients computed with a single
+// inversion. Shares which are nil, out of [0, n) or duplicated are skipped.
+func RecoverSecret(g abstract.Group, shares []*share.PriShare, t, n int) (abstract.Scalar, error) {
+	indices, ys := selectPriShares(shares, t, n)
+	if len(ys) < t {
+		return nil, ErrNotEnoughShares
+	}
+	coeffs, err := LagrangeAtZero(g, indices)
+	if err != nil {
+		return nil, err
+	}
+	secret := g.Scalar().Zero()
+	tmp := g.Scalar()
+	for i, c := range coeffs {
+		secret.Add(secret, tmp.Mul(c, ys[i]))
+	}
+	pbc.DestroyScalar(tmp)
+	return secret, nil
+}
+
+// RecoverPriPoly recovers the whole polynomial of degree t - 1 from t of the
+// shares.
+func RecoverPriPoly(g abstract.Group, shares []*share.PriShare, t, n int) (*Poly, error) {
+	indices, ys := selectPriShares(shares, t, n)
+	if len(ys) < t {
+		return nil, ErrNotEnoughShares
+	}
+	return Interpolate(g, indexPoints(g, indices), ys)
+}
+
+// RecoverCommit recovers the commitment to the secret from t of the public
+// shares, like share.RecoverCommit: it is the interpolation at 0 in the
+// exponent, computed with a single multi-scalar multiplication.
+func RecoverCommit(g abstract.Group, shares []*share.PubShare, t, n int) (abstract.Point, error) {
+	indices, ys := selectPubShares(shares, t, n)
+	if len(ys) < t {
+		return nil, ErrNotEnoughShares
+	}
+	coeffs, err := LagrangeAtZero(g, indices)
+	if err != nil {
+		return nil, err
+	}
+	return pbc.MultiMul(g, ys, coeffs), nil
+}
+
+// RecoverPubPoly recovers the whole public polynomial from t of the public
+// shares, interpolating each commitment in the exponent. The base point of
+// the result is the generator of g.
+func RecoverPubPoly(g abstract.Group, shares []*share.PubShare, t, n int) (*share.PubPoly, error) {
+	indices, ys := selectPubShares(shares, t, n)
+	if len(ys) < t {
+		return nil, ErrNotEnoughShares
+	}
+	basis, err := lagrangeBasis(g, indexPoints(g, indices))
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]abstract.Point, t)
+	column := make([]abstract.Scalar, t)
+	for k := range commits {
+		for i := range basis {
+			column[i] = basis[i][k]
+		}
+		commits[k] = pbc.MultiMul(g, ys, column)
+	}
+	return share.NewPubPoly(g, nil, commits), nil
+}
+
+// selectPriShares returns the indices and values of the first t valid and
+// distinct shares.
+func selectPriShares(shares []*share.PriShare, t, n int) ([]int, []abstract.Scalar) {
+	indices := make([]int, 0, t)
+	ys := make([]abstract.Scalar, 0, t)
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I < 0 || s.I >= n || seen[s.I] {
+			continue
+		}
+		seen[s.I] = true
+		indices = append(indices, s.I)
+		ys = append(ys, s.V)
+		if len(ys) == t {
+			break
+		}
+	}
+	return indices, ys
+}
+
+// selectPubShares is the version of selectPriShares for public shares.
+func selectPubShares(shares []*share.PubShare, t, n int) ([]int, []abstract.Point) {
+	indices := make([]int, 0, t)
+	ys := make([]abstract.Point, 0, t)
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I < 0 || s.I >= n || seen[s.I] {
+			continue
+		}
+		seen[s.I] = true
+		indices = append(indices, s.I)
+		ys = append(ys, s.V)
+		if len(ys) == t {
+			break
+		}
+	}
+	return indices, ys
+}
+
+// indexPoints returns the points index + 1 of the shares of the given
+// indices.
+func indexPoints(g abstract.Group, indices []int) []abstract.Scalar {
+	xs := make([]abstract.Scalar, len(indices))
+	for i, idx := range indices {
+		xs[i] = g.Scalar().SetInt64(1 + int64(idx))
+	}
+	return xs
+}
+
+// checkDistinct returns ErrDuplicatePoint if two of the points are equal.
+func checkDistinct(xs []abstract.Scalar) error {
+	seen := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		buff, err := x.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if seen[string(buff)] {
+			return ErrDuplicatePoint
+		}
+		seen[string(buff)] = true
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+)
+
+// nttMin is the number of coefficients of both factors from which Mul uses
+// the number theoretic transform instead of the schoolbook method.
+const nttMin = 64
+
+// ErrNoRootOfUnity is returned by NewDomain when the scalars of the group have
+// no root of unity of the requested order: r - 1 is divisible by 2^32 on
+// BLS12-381 but only by 4 on the BN curves of the pbc package.
+var ErrNoRootOfUnity = errors.New("poly: no root of unity of that order")
+
+// Domain is the multiplicative subgroup of order n, a power of two, of the
+// scalars of a group, over which the number theoretic transform evaluates
+// and interpolates polynomials of degree lower than n.
+type Domain struct {
+	g     abstract.Group
+	n     int
+	roots []abstract.Scalar // roots[i] = omega^i for i < n/2
+	inv   []abstract.Scalar // inv[i] = omega^-i for i < n/2
+	nInv  abstract.Scalar
+}
+
+// NewDomain returns the domain of order n of the scalars of g, which must be
+// a power of two. The scalars must implement pbc.BigScalar.
+func NewDomain(g abstract.Group, n int) (*Domain, error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("poly: domain order %d is not a power of two", n)
+	}
+	omega, err := rootOfUnity(g, n)
+	if err != nil {
+		return nil, err
+	}
+	d := &Domain{g: g, n: n}
+	omegaInv := g.Scalar().Inv(omega)
+	d.roots = powers(g, omega, n/2)
+	d.inv = powers(g, omegaInv, n/2)
+	d.nInv = g.Scalar().Inv(g.Scalar().SetInt64(int64(n)))
+	return d, nil
+}
+
+// Size returns the order of the domain.
+func (d *Domain) Size() int {
+	return d.n
+}
+
+// FFT returns the values of the polynomial of coefficients coeffs at the
+// powers omega^0, ..., omega^(n-1) of the generator of the domain. There must
+// be at most n coefficients.
+func (d *Domain) FFT(coeffs []abstract.Scalar) []abstract.Scalar {
+	return d.transform(coeffs, d.roots)
+}
+
+// InverseFFT returns the coefficients of the polynomial of degree lower than
+// n taking the given values at the powers of the generator of the domain.
+func (d *Domain) InverseFFT(values []abstract.Scalar) []abstract.Scalar {
+	res := d.transform(values, d.inv)
+	for _, c := range res {
+		c.Mul(c, d.nInv)
+	}
+	return res
+}
+
+// transform is the iterative radix-2 Cooley-Tukey transform with the given
+// twiddle factors, on a copy of a padded with zeros.
+func (d *Domain) transform(a []abstract.Scalar, twiddles []abstract.Scalar) []abstract.Scalar {
+	if len(a) > d.n {
+		panic("poly: more values than the order of the domain")
+	}
+	res := make([]abstract.Scalar, d.n)
+	for i := range res {
+		res[i] = d.g.Scalar().Zero()
+	}
+	for i, v := range a {
+		res[bitReverse(i, d.n)].Set(v)
+	}
+	t := d.g.Scalar()
+	for size := 2; size <= d.n; size <<= 1 {
+		half, step := size/2, d.n/size
+		for start := 0; start < d.n; start += size {
+			for j := 0; j < half; j++ {
+				u, v := res[start+j], res[start+j+half]
+				t.Mul(v, twiddles[j*step])
+				v.Sub(u, t)
+				u.Add(u, t)
+			}
+		}
+	}
+	return res
+}
+
+// bitReverse returns i with its log2(n) bits in the reverse order.
+func bitReverse(i, n int) int {
+	r := 0
+	for n >>= 1; n > 0; n >>= 1 {
+		r = r<<1 | i&1
+		i >>= 1
+	}
+	return r
+}
+
+// powers returns x^0, ..., x^(n-1).
+func powers(g abstract.Group, x abstract.Scalar, n int) []abstract.Scalar {
+	res := make([]abstract.Scalar, n)
+	for i := range res {
+		if i == 0 {
+			res[i] = g.Scalar().One()
+			continue
+		}
+		res[i] = g.Scalar().Mul(res[i-1], x)
+	}
+	return res
+}
+
+// rootOfUnity returns a primitive n-th root of unity of the scalars of g,
+// derived from the first small integer which is not a square so its power
+// (r - 1) / 2^s has order exactly 2^s, the largest power of two dividing
+// r - 1.
+func rootOfUnity(g abstract.Group, n int) (abstract.Scalar, error) {
+	minusOne, ok := g.Scalar().SetInt64(-1).(pbc.BigScalar)
+	if !ok {
+		return nil, ErrNoRootOfUnity
+	}
+	r := new(big.Int).Add(minusOne.Big(), big.NewInt(1))
+	m := new(big.Int).Sub(r, big.NewInt(1))
+	s := uint(0)
+	for m.Bit(int(s)) == 0 {
+		s++
+	}
+	if big.NewInt(int64(n)).Cmp(new(big.Int).Lsh(big.NewInt(1), s)) > 0 {
+		return nil, ErrNoRootOfUnity
+	}
+	odd := new(big.Int).Rsh(m, s)
+	half := new(big.Int).Rsh(m, 1)
+	for c := int64(2); ; c++ {
+		x := big.NewInt(c)
+		// Euler's criterion: c is a square if c^((r-1)/2) = 1
+		if new(big.Int).Exp(x, half, r).Cmp(big.NewInt(1)) == 0 {
+			continue
+		}
+		w := new(big.Int).Exp(x, odd, r)
+		w.Exp(w, new(big.Int).Lsh(big.NewInt(1), s-uint(log2(n))), r)
+		return g.Scalar().(pbc.BigScalar).SetBig(w), nil
+	}
+}
+
+// log2 returns the base 2 logarithm of the power of two n.
+func log2(n int) int {
+	l := 0
+	for ; n > 1; n >>= 1 {
+		l++
+	}
+	return l
+}
+
+var domains = struct {
+	sync.Mutex
+	m map[string]*Domain
+}{m: make(map[string]*Domain)}
+
+// domainFor returns the cached domain of order n of g, or nil if there is
+// none.
+func domainFor(g abstract.Group, n int) *Domain {
+	key := fmt.Sprintf("%s/%d", g, n)
+	domains.Lock()
+	defer domains.Unlock()
+	if d, ok := domains.m[key]; ok {
+		return d
+	}
+	d, err := NewDomain(g, n)
+	if err != nil {
+		d = nil
+	}
+	domains.m[key] = d
+	return d
+}
+
+// mulNTT returns p * q computed by pointwise multiplication of the
+// transforms, or false if the group has no domain large enough.
+func (p *Poly) mulNTT(q *Poly) (*Poly, bool) {
+	size := len(p.coeffs) + len(q.coeffs) - 1
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	d := domainFor(p.g, n)
+	if d == nil {
+		return nil, false
+	}
+	a := d.FFT(p.coeffs)
+	b := d.FFT(q.coeffs)
+	for i := range a {
+		a[i].Mul(a[i], b[i])
+	}
+	coeffs := d.InverseFFT(a)[:size]
+	return (&Poly{g: p.g, coeffs: coeffs}).trim(), true
+}
@@ -0,0 +1,238 @@
+// Package poly implements the arithmetic of polynomials over the scalars of a
+// group: evaluation, addition, multiplication and division, the number
+// theoretic transform when the order of the group allows it, and the
+// Lagrange interpolation of shares, of scalars or in the exponent. It works
+// with any abstract.Group but is meant for the groups of the pbc package, for
+// which it uses multi-scalar multiplications and constant time
+// multiplications of the secret coefficients.
+package poly
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+// Poly is a polynomial whose coefficients are scalars of a group, the
+// coefficient of x^i at index i. The zero polynomial has no coefficients.
+type Poly struct {
+	g      abstract.Group
+	coeffs []abstract.Scalar
+}
+
+// New returns the polynomial of the given coefficients, the constant one
+// first. The coefficients are copied.
+func New(g abstract.Group, coeffs ...abstract.Scalar) *Poly {
+	p := &Poly{g: g, coeffs: make([]abstract.Scalar, len(coeffs))}
+	for i, c := range coeffs {
+		p.coeffs[i] = g.Scalar().Set(c)
+	}
+	return p.trim()
+}
+
+// NewRandom returns a random polynomial of degree t - 1 whose constant
+// coefficient is s, or random if s is nil, like share.NewPriPoly.
+func NewRandom(g abstract.Group, t int, s abstract.Scalar, rand cipher.Stream) *Poly {
+	coeffs := make([]abstract.Scalar, t)
+	for i := range coeffs {
+		coeffs[i] = g.Scalar().Pick(rand)
+	}
+	if s != nil {
+		coeffs[0].Set(s)
+	}
+	return &Poly{g: g, coeffs: coeffs}
+}
+
+// Group returns the group of the coefficients.
+func (p *Poly) Group() abstract.Group {
+	return p.g
+}
+
+// Degree returns the degree of p, -1 for the zero polynomial.
+func (p *Poly) Degree() int {
+	return len(p.coeffs) - 1
+}
+
+// Coefficients returns a copy of the coefficients of p, the constant one
+// first.
+func (p *Poly) Coefficients() []abstract.Scalar {
+	res := make([]abstract.Scalar, len(p.coeffs))
+	for i, c := range p.coeffs {
+		res[i] = c.Clone()
+	}
+	return res
+}
+
+// Secret returns the constant coefficient of p.
+func (p *Poly) Secret() abstract.Scalar {
+	if len(p.coeffs) == 0 {
+		return p.g.Scalar().Zero()
+	}
+	return p.coeffs[0].Clone()
+}
+
+// Eval returns p(x), computed with Horner's rule.
+func (p *Poly) Eval(x abstract.Scalar) abstract.Scalar {
+	v := p.g.Scalar().Zero()
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		v.Mul(v, x)
+		v.Add(v, p.coeffs[i])
+	}
+	return v
+}
+
+// Share returns the share of index i of p, p(i + 1), like share.PriPoly.Eval.
+func (p *Poly) Share(i int) *share.PriShare {
+	return &share.PriShare{I: i, V: p.Eval(p.g.Scalar().SetInt64(1 + int64(i)))}
+}
+
+// Shares returns the shares of index 0 to n - 1 of p.
+func (p *Poly) Shares(n int) []*share.PriShare {
+	shares := make([]*share.PriShare, n)
+	for i := range shares {
+		shares[i] = p.Share(i)
+	}
+	return shares
+}
+
+// Equal returns true if p and q are the same polynomial.
+func (p *Poly) Equal(q *Poly) bool {
+	if len(p.coeffs) != len(q.coeffs) {
+		return false
+	}
+	for i := range p.coeffs {
+		if !p.coeffs[i].Equal(q.coeffs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns p + q.
+func (p *Poly) Add(q *Poly) *Poly {
+	return p.combine(q, false)
+}
+
+// Sub returns p - q.
+func (p *Poly) Sub(q *Poly) *Poly {
+	return p.combine(q, true)
+}
+
+func (p *Poly) combine(q *Poly, sub bool) *Poly {
+	n := len(p.coeffs)
+	if len(q.coeffs) > n {
+		n = len(q.coeffs)
+	}
+	coeffs := make([]abstract.Scalar, n)
+	for i := range coeffs {
+		c := p.g.Scalar().Zero()
+		if i < len(p.coeffs) {
+			c.Set(p.coeffs[i])
+		}
+		if i < len(q.coeffs) {
+			if sub {
+				c.Sub(c, q.coeffs[i])
+			} else {
+				c.Add(c, q.coeffs[i])
+			}
+		}
+		coeffs[i] = c
+	}
+	return (&Poly{g: p.g, coeffs: coeffs}).trim()
+}
+
+// Mul returns p * q. Large products are computed with the number theoretic
+// transform when the order of the group has a large enough power of two
+// subgroup, and with the schoolbook method otherwise.
+func (p *Poly) Mul(q *Poly) *Poly {
+	if len(p.coeffs) == 0 || len(q.coeffs) == 0 {
+		return &Poly{g: p.g}
+	}
+	if len(p.coeffs) >= nttMin && len(q.coeffs) >= nttMin {
+		if r, ok := p.mulNTT(q); ok {
+			return r
+		}
+	}
+	coeffs := make([]abstract.Scalar, len(p.coeffs)+len(q.coeffs)-1)
+	for i := range coeffs {
+		coeffs[i] = p.g.Scalar().Zero()
+	}
+	t := p.g.Scalar()
+	for i, a := range p.coeffs {
+		for j, b := range q.coeffs {
+			coeffs[i+j].Add(coeffs[i+j], t.Mul(a, b))
+		}
+	}
+	return (&Poly{g: p.g, coeffs: coeffs}).trim()
+}
+
+// ErrDivisionByZero is returned when dividing by the zero polynomial.
+var ErrDivisionByZero = errors.New("poly: division by the zero polynomial")
+
+// DivMod returns the quotient and the remainder of the euclidean division of
+// p by q.
+func (p *Poly) DivMod(q *Poly) (quo, rem *Poly, err error) {
+	if len(q.coeffs) == 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	r := p.Coefficients()
+	dq := len(q.coeffs) - 1
+	if len(r) <= dq {
+		return &Poly{g: p.g}, &Poly{g: p.g, coeffs: r}, nil
+	}
+	lead := p.g.Scalar().Inv(q.coeffs[dq])
+	coeffs := make([]abstract.Scalar, len(r)-dq)
+	t := p.g.Scalar()
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		c := p.g.Scalar().Mul(r[i+dq], lead)
+		for j, b := range q.coeffs {
+			r[i+j].Sub(r[i+j], t.Mul(c, b))
+		}
+		coeffs[i] = c
+	}
+	quo = (&Poly{g: p.g, coeffs: coeffs}).trim()
+	rem = (&Poly{g: p.g, coeffs: r[:dq]}).trim()
+	return quo, rem, nil
+}
+
+// Commit returns the public polynomial of p with base point b, or the
+// generator of the group if b is nil. The coefficients are multiplied in
+// constant time when the points of the group support it.
+func (p *Poly) Commit(b abstract.Point) *share.PubPoly {
+	commits := make([]abstract.Point, len(p.coeffs))
+	for i, c := range p.coeffs {
+		commits[i] = secretPoint(p.g).Mul(b, c)
+	}
+	return share.NewPubPoly(p.g, b, commits)
+}
+
+// Destroy wipes the coefficients of p, see pbc.DestroyScalar.
+func (p *Poly) Destroy() {
+	for _, c := range p.coeffs {
+		pbc.DestroyScalar(c)
+	}
+}
+
+// trim removes the zero coefficients of the highest degrees.
+func (p *Poly) trim() *Poly {
+	zero := p.g.Scalar().Zero()
+	n := len(p.coeffs)
+	for n > 0 && p.coeffs[n-1].Equal(zero) {
+		n--
+	}
+	p.coeffs = p.coeffs[:n]
+	return p
+}
+
+// secretPoint returns a new point of g which multiplies in constant time if
+// the group supports it.
+func secretPoint(g abstract.Group) abstract.Point {
+	p := g.Point()
+	if v, ok := p.(pbc.VarTimer); ok {
+		v.SetVarTime(false)
+	}
+	return p
+}
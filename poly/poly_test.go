@@ -0,0 +1,178 @@
+package poly
+
+import (
+	"testing"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dedis/crypto.v0/abstract"
+	"gopkg.in/dedis/crypto.v0/random"
+	"gopkg.in/dedis/crypto.v0/share"
+)
+
+var pairing = pbc.NewPairingFp254BNb()
+var suite = pairing.G2()
+
+func randomPoly(g abstract.Group, t int) *Poly {
+	return NewRandom(g, t, nil, random.Stream)
+}
+
+func TestPolyArithmetic(t *testing.T) {
+	p := randomPoly(suite, 5)
+	q := randomPoly(suite, 3)
+	x := suite.Scalar().Pick(random.Stream)
+	px, qx := p.Eval(x), q.Eval(x)
+
+	require.Equal(t, 4, p.Degree())
+	require.True(t, p.Add(q).Eval(x).Equal(suite.Scalar().Add(px, qx)))
+	require.True(t, p.Sub(q).Eval(x).Equal(suite.Scalar().Sub(px, qx)))
+	require.True(t, p.Mul(q).Eval(x).Equal(suite.Scalar().Mul(px, qx)))
+	require.Equal(t, 6, p.Mul(q).Degree())
+	require.Equal(t, -1, p.Sub(p).Degree())
+
+	quo, rem, err := p.DivMod(q)
+	require.Nil(t, err)
+	require.Equal(t, 2, quo.Degree())
+	require.True(t, rem.Degree() < q.Degree())
+	require.True(t, quo.Mul(q).Add(rem).Equal(p))
+
+	_, _, err = p.DivMod(p.Sub(p))
+	require.Equal(t, ErrDivisionByZero, err)
+
+	// the shares are the ones of share.PriPoly
+	require.True(t, p.Share(3).V.Equal(p.Eval(suite.Scalar().SetInt64(4))))
+	pub := p.Commit(nil)
+	for _, s := range p.Shares(5) {
+		require.True(t, pub.Check(s))
+	}
+	require.True(t, pub.Commit().Equal(suite.Point().Mul(nil, p.Secret())))
+}
+
+func TestBatchInvert(t *testing.T) {
+	xs := make([]abstract.Scalar, 10)
+	for i := range xs {
+		xs[i] = suite.Scalar().Pick(random.Stream)
+	}
+	inv := make([]abstract.Scalar, len(xs))
+	for i := range inv {
+		inv[i] = xs[i].Clone()
+	}
+	require.Nil(t, BatchInvert(suite, inv))
+	for i := range xs {
+		require.True(t, suite.Scalar().Mul(xs[i], inv[i]).Equal(suite.Scalar().One()))
+	}
+
+	inv[3].Zero()
+	saved := inv[0].Clone()
+	require.Equal(t, ErrInverseOfZero, BatchInvert(suite, inv))
+	require.True(t, inv[0].Equal(saved))
+}
+
+func TestInterpolation(t *testing.T) {
+	n, th := 9, 5
+	p := randomPoly(suite, th)
+	shares := p.Shares(n)
+
+	secret, err := RecoverSecret(suite, shares[2:], th, n)
+	require.Nil(t, err)
+	require.True(t, secret.Equal(p.Secret()))
+	expected, err := share.RecoverSecret(suite, shares[2:], th, n)
+	require.Nil(t, err)
+	require.True(t, secret.Equal(expected))
+
+	rec, err := RecoverPriPoly(suite, shares[4:], th, n)
+	require.Nil(t, err)
+	require.True(t, rec.Equal(p))
+
+	_, err = RecoverSecret(suite, shares[:th-1], th, n)
+	require.Equal(t, ErrNotEnoughShares, err)
+	// duplicated and out of range shares are skipped
+	bad := []*share.PriShare{shares[0], shares[0], {I: n, V: shares[1].V}, nil}
+	_, err = RecoverSecret(suite, append(bad, shares[1:th-1]...), th, n)
+	require.Equal(t, ErrNotEnoughShares, err)
+
+	// in the exponent
+	pub := p.Commit(nil)
+	pubShares := pub.Shares(n)
+	commit, err := RecoverCommit(suite, pubShares[3:], th, n)
+	require.Nil(t, err)
+	require.True(t, commit.Equal(pub.Commit()))
+	// duplicated and missing shares are skipped
+	badPub := append([]*share.PubShare{nil, pubShares[0]}, pubShares[:th-1]...)
+	_, err = RecoverCommit(suite, badPub, th, n)
+	require.NotNil(t, err)
+	commit, err = RecoverCommit(suite, append(badPub, pubShares[th]), th, n)
+	require.Nil(t, err)
+	require.True(t, commit.Equal(pub.Commit()))
+	pubRec, err := RecoverPubPoly(suite, pubShares[1:], th, n)
+	require.Nil(t, err)
+	require.True(t, pubRec.Equal(pub))
+
+	// at any point
+	x := suite.Scalar().Pick(random.Stream)
+	xs := indexPoints(suite, []int{0, 2, 4, 6, 8})
+	coeffs, err := LagrangeCoefficients(suite, xs, x)
+	require.Nil(t, err)
+	v := suite.Scalar().Zero()
+	for i, c := range coeffs {
+		v.Add(v, suite.Scalar().Mul(c, p.Eval(xs[i])))
+	}
+	require.True(t, v.Equal(p.Eval(x)))
+
+	coeffs, err = LagrangeCoefficients(suite, xs, xs[2])
+	require.Nil(t, err)
+	require.True(t, coeffs[2].Equal(suite.Scalar().One()))
+	require.True(t, coeffs[0].Equal(suite.Scalar().Zero()))
+
+	_, err = LagrangeCoefficients(suite, append(xs, xs[1]), x)
+	require.Equal(t, ErrDuplicatePoint, err)
+}
+
+func TestNTT(t *testing.T) {
+	_, err := NewDomain(suite, 8)
+	require.Equal(t, ErrNoRootOfUnity, err)
+	_, err = NewDomain(suite, 6)
+	require.Error(t, err)
+
+	if !pbc.Supported(pbc.CurveBLS12_381) {
+		t.Skip("BLS12-381 not supported by the backend")
+	}
+	pairing, err := pbc.NewPairingBLS12_381()
+	require.Nil(t, err)
+	g := pairing.G1()
+	d, err := NewDomain(g, 16)
+	require.Nil(t, err)
+	require.Equal(t, 16, d.Size())
+
+	p := randomPoly(g, 11)
+	values := d.FFT(p.coeffs)
+	omega := d.roots[1]
+	x := g.Scalar().One()
+	for i := range values {
+		require.True(t, values[i].Equal(p.Eval(x)), "%d", i)
+		x.Mul(x, omega)
+	}
+	require.True(t, x.Equal(g.Scalar().One()))
+	require.True(t, New(g, d.InverseFFT(values)...).Equal(p))
+
+	// large products go through the transform
+	a := randomPoly(g, nttMin+3)
+	b := randomPoly(g, nttMin)
+	prod, ok := a.mulNTT(b)
+	require.True(t, ok)
+	y := g.Scalar().Pick(random.Stream)
+	require.True(t, prod.Eval(y).Equal(g.Scalar().Mul(a.Eval(y), b.Eval(y))))
+	require.True(t, prod.Equal(a.Mul(b)))
+}
+
+func BenchmarkRecoverCommit(b *testing.B) {
+	n, th := 100, 51
+	pub := randomPoly(suite, th).Commit(nil)
+	shares := pub.Shares(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := RecoverCommit(suite, shares, th, n); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,49 @@
 package protocol
 
-import "github.com/dedis/paper_17_dfinity/pbc"
+import (
+	"fmt"
 
-// Ugly hack to have a fixed pairing for compile time since switching at runtime
-// does segfault for the moment. See https://github.com/dfinity/bn/issues/8 .
+	"github.com/dedis/paper_17_dfinity/bls"
+	"github.com/dedis/paper_17_dfinity/pbc"
+)
+
+// pairing is the curve used by default by the DKG and TBLS protocols. Other
+// curves can be used alongside it since the pbc package takes care of switching
+// the C library's context.
 var pairing = pbc.NewPairingFp254BNb()
+
+// ciphersuite is the domain separation tag of the threshold signatures
+// produced by the TBLS protocol and multisigCiphersuite the one of the
+// accountable multisignatures of the nodes. Both name the hash to curve of G1
+// of the curve in use, so they change with SetCurve.
+var ciphersuite, multisigCiphersuite = ciphersuites(pbc.CurveFp254BNb)
+
+// SetCurve selects the curve used by the DKG and TBLS protocols by its name,
+// e.g. "bls12_381". It must be called before any protocol runs. BLS12-381 is
+// only implemented by the purego backend, so it needs a build with the
+// "purego" tag.
+func SetCurve(name string) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("protocol: %v", e)
+		}
+	}()
+	curve := pbc.Curve(name)
+	if !pbc.Supported(curve) {
+		if curve == pbc.CurveBLS12_381 {
+			return fmt.Errorf("protocol: curve %s is not supported by the %s backend, build with -tags purego", name, pbc.Backend)
+		}
+		return fmt.Errorf("protocol: curve %s not supported by the %s backend", name, pbc.Backend)
+	}
+	pairing = pbc.NewPairing(curve)
+	ciphersuite, multisigCiphersuite = ciphersuites(curve)
+	return nil
+}
+
+// ciphersuites returns the domain separation tags of the threshold signatures
+// and of the multisignatures on curve.
+func ciphersuites(curve int) (tbls, multisig bls.Ciphersuite) {
+	h2c := bls.HashToG1Suite(curve)
+	return bls.Ciphersuite("BLS_SIG_" + h2c + "NUL_DFINITY_TBLS_"),
+		bls.Ciphersuite("BLS_SIG_" + h2c + "POP_DFINITY_MULTISIG_")
+}
@@ -0,0 +1,29 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dedis/paper_17_dfinity/pbc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCurve(t *testing.T) {
+	defer func() {
+		require.Nil(t, SetCurve("fp254nb"))
+		require.Equal(t, pbc.CurveFp254BNb, pairing.Curve())
+	}()
+
+	err := SetCurve("bls12_381")
+	if !pbc.Supported(pbc.CurveBLS12_381) {
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "purego")
+		return
+	}
+	require.Nil(t, err)
+	require.Equal(t, pbc.CurveBLS12_381, pairing.Curve())
+	for _, cs := range []string{string(ciphersuite), string(multisigCiphersuite)} {
+		require.True(t, strings.HasPrefix(cs, "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_"), cs)
+	}
+	require.NotEqual(t, ciphersuite, multisigCiphersuite)
+}
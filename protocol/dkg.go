@@ -113,6 +113,11 @@ func (d *DkgProto) Start() error {
 func (d *DkgProto) OnDeal(dm DealMsg) error {
 	log.Lvl2(d.Name(), " received deal from ", dm.TreeNode.Name())
 	d.Lock()
+	if d.done {
+		// late or replayed deal
+		d.Unlock()
+		return nil
+	}
 	if !d.sentDeal {
 		d.sentDeal = true
 		if err := d.sendDeals(); err != nil {
@@ -153,6 +158,11 @@ func (d *DkgProto) processAllTempResponses(dealIndex uint32) {
 
 func (d *DkgProto) OnResponse(rm ResponseMsg) error {
 	d.Lock()
+	if d.done {
+		// late or replayed response
+		d.Unlock()
+		return nil
+	}
 	defer d.checkCertified()
 	defer d.Unlock()
 	d.responsesReceived++
@@ -222,3 +232,13 @@ func (d *DkgProto) checkCertified() {
 	d.dkgDoneCb(dks)
 	d.done = true
 }
+
+// Shutdown wipes the deals and shares held by the generator when the
+// protocol instance ends. Until then, the deals and responses received once
+// the node is certified are dropped.
+func (d *DkgProto) Shutdown() error {
+	d.Lock()
+	defer d.Unlock()
+	d.dkg.Destroy()
+	return nil
+}
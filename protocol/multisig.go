@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"errors"
+
+	"github.com/dedis/paper_17_dfinity/bls"
+	"gopkg.in/dedis/onet.v1"
+	"gopkg.in/dedis/onet.v1/network"
+)
+
+func init() {
+	network.RegisterMessage(bls.MultiSig{})
+}
+
+// MultiSign returns the index of the node in the roster of the context and
+// its signature of msg, to be aggregated by bls.AggregateMultiSig.
+func (s *Service) MultiSign(msg []byte) (int, []byte) {
+	return s.Context.Index, bls.Sign(pairing, multisigCiphersuite, s.Context.Private, msg)
+}
+
+// VerifyMultiSig checks the multisignature m of msg by at least min nodes
+// and returns the server identities of its signers. Bit i of the bitmap
+// stands for the node r.List[i], whose public key is the i-th one of the
+// context, as dispatched by BroadcastPBCContext and registered with its proof
+// of possession when the context was set up. r must list the nodes of the
+// roster bound to the context, in the same order.
+func (s *Service) VerifyMultiSig(r *onet.Roster, msg []byte, m *bls.MultiSig, min int) ([]*network.ServerIdentity, error) {
+	if s.registry == nil || !sameNodes(r, s.onetRoster) {
+		return nil, errors.New("protocol: roster does not match the context")
+	}
+	if err := bls.VerifyMultiSig(s.registry, multisigCiphersuite, msg, m, min); err != nil {
+		return nil, err
+	}
+	return RosterSigners(r, m.Signers)
+}
+
+// sameNodes tells whether a and b list the same nodes in the same order.
+func sameNodes(a, b *onet.Roster) bool {
+	if a == nil || b == nil || len(a.List) != len(b.List) {
+		return false
+	}
+	for i, si := range a.List {
+		if !si.Equal(b.List[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// RosterSigners returns the server identities of r set in the bitmap, in the
+// order of the roster.
+func RosterSigners(r *onet.Roster, signers bls.Bitmap) ([]*network.ServerIdentity, error) {
+	b, err := bls.DecodeBitmap(signers, len(r.List))
+	if err != nil {
+		return nil, err
+	}
+	var ids []*network.ServerIdentity
+	for _, i := range b.Indices() {
+		ids = append(ids, r.List[i])
+	}
+	return ids, nil
+}
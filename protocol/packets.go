@@ -1,12 +1,17 @@
 package protocol
 
 import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"reflect"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/dedis/paper_17_dfinity/bls"
+	"github.com/dedis/paper_17_dfinity/pbc"
 	"github.com/dedis/paper_17_dfinity/pedersen/dkg"
-	"github.com/dedis/protobuf"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/onet.v1"
 	"gopkg.in/dedis/onet.v1/network"
@@ -37,10 +42,126 @@ type PBCContext struct {
 	Roster    []abstract.Point
 	Private   abstract.Scalar
 	Threshold int
+	// Proofs[i] is the proof of possession of the private key of Roster[i],
+	// checked by the nodes before they accept multisignatures of the roster.
+	Proofs [][]byte
 }
 
+// pbcContextJSON is the JSON form of a PBCContext, with the points and scalars
+// in the text form of the pbc package which tells their curve and group.
+type pbcContextJSON struct {
+	Index     int
+	Roster    []string
+	Private   string
+	Threshold int
+	Proofs    [][]byte
+}
+
+func (c *PBCContext) MarshalJSON() ([]byte, error) {
+	cj := &pbcContextJSON{Index: c.Index, Threshold: c.Threshold, Proofs: c.Proofs}
+	for _, p := range c.Roster {
+		text, err := p.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		cj.Roster = append(cj.Roster, string(text))
+	}
+	text, err := c.Private.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	cj.Private = string(text)
+	return json.Marshal(cj)
+}
+
+func (c *PBCContext) UnmarshalJSON(buff []byte) error {
+	var cj pbcContextJSON
+	if err := json.Unmarshal(buff, &cj); err != nil {
+		return err
+	}
+	roster := make([]abstract.Point, len(cj.Roster))
+	for i, text := range cj.Roster {
+		p, err := pbc.ParsePoint([]byte(text))
+		if err != nil {
+			return err
+		}
+		roster[i] = p
+	}
+	private, err := pbc.ParseScalar([]byte(cj.Private))
+	if err != nil {
+		return err
+	}
+	c.Index, c.Roster, c.Private, c.Threshold = cj.Index, roster, private, cj.Threshold
+	c.Proofs = cj.Proofs
+	return nil
+}
+
+// MarshalText returns the index, the threshold, the private key and the
+// roster of c separated by spaces, each public key followed by its proof of
+// possession in hexadecimal, so a PBCContext can be stored as a single string
+// in a TOML configuration file.
+func (c *PBCContext) MarshalText() ([]byte, error) {
+	if len(c.Proofs) != len(c.Roster) {
+		return nil, errors.New("protocol: roster without proofs of possession")
+	}
+	parts := []string{strconv.Itoa(c.Index), strconv.Itoa(c.Threshold)}
+	text, err := c.Private.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	parts = append(parts, string(text))
+	for i, p := range c.Roster {
+		text, err := p.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, string(text), hex.EncodeToString(c.Proofs[i]))
+	}
+	return []byte(strings.Join(parts, " ")), nil
+}
+
+// UnmarshalText decodes the output of MarshalText.
+func (c *PBCContext) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) < 3 || len(fields)%2 == 0 {
+		return errors.New("protocol: invalid text encoding of a PBCContext")
+	}
+	index, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return err
+	}
+	threshold, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return err
+	}
+	private, err := pbc.ParseScalar([]byte(fields[2]))
+	if err != nil {
+		return err
+	}
+	n := (len(fields) - 3) / 2
+	roster := make([]abstract.Point, n)
+	proofs := make([][]byte, n)
+	for i := range roster {
+		p, err := pbc.ParsePoint([]byte(fields[3+2*i]))
+		if err != nil {
+			return err
+		}
+		proof, err := hex.DecodeString(fields[4+2*i])
+		if err != nil {
+			return err
+		}
+		roster[i], proofs[i] = p, proof
+	}
+	c.Index, c.Roster, c.Private, c.Threshold = index, roster, private, threshold
+	c.Proofs = proofs
+	return nil
+}
+
+// PBCRaw carries the encoded context of a node and the roster of the nodes,
+// in the order of the public keys of the context.
 type PBCRaw struct {
 	Context []byte
+	Roster  *onet.Roster
 }
 
 type PBCContextACK struct {
@@ -72,12 +193,6 @@ type DKGProxy struct{}
 func (p *DKGProxy) Wrap(msg interface{}, info *onet.OverlayMsg) (interface{}, error) {
 	dkgPacket := &DKGPacket{Om: info}
 	var err error
-	if msg != nil {
-		dkgPacket.Buff, err = protobuf.Encode(msg)
-		if err != nil {
-			return nil, err
-		}
-	}
 	//log.LLvl2("DKGProxy -> Wrap() ", msg)
 	switch msg.(type) {
 	case *dkg.Deal:
@@ -89,6 +204,10 @@ func (p *DKGProxy) Wrap(msg interface{}, info *onet.OverlayMsg) (interface{}, er
 	default:
 		dkgPacket.Type = DKGOm
 		dkgPacket.Buff = make([]byte, 0)
+		return dkgPacket, nil
+	}
+	if dkgPacket.Buff, err = encode(msg, pairing.G2()); err != nil {
+		return nil, err
 	}
 	return dkgPacket, nil
 }
@@ -110,7 +229,7 @@ func (p *DKGProxy) Unwrap(msg interface{}) (interface{}, *onet.OverlayMsg, error
 		//log.LLvl2("DKGProxy -> Unwrap() OverlayMessage")
 		return nil, dkgPacket.Om, nil
 	default:
-		panic("I'm freaking out DKG")
+		return nil, nil, fmt.Errorf("dkgproxy: unknown packet type %d", dkgPacket.Type)
 	}
 	if err := decode(dkgPacket.Buff, ret, pairing.G2()); err != nil {
 		return nil, nil, err
@@ -145,13 +264,6 @@ type TBLSProxy struct{}
 
 func (p *TBLSProxy) Wrap(msg interface{}, info *onet.OverlayMsg) (interface{}, error) {
 	bPacket := &TBLSPacket{Om: info}
-	if msg != nil {
-		var err error
-		bPacket.Buff, err = protobuf.Encode(msg)
-		if err != nil {
-			return nil, err
-		}
-	}
 	switch msg.(type) {
 	case *TBLSRequest:
 		bPacket.Type = TBLSRequestType
@@ -160,6 +272,11 @@ func (p *TBLSProxy) Wrap(msg interface{}, info *onet.OverlayMsg) (interface{}, e
 	default:
 		bPacket.Type = TBLSOm
 		bPacket.Buff = make([]byte, 0)
+		return bPacket, nil
+	}
+	var err error
+	if bPacket.Buff, err = encode(msg, pairing.G1()); err != nil {
+		return nil, err
 	}
 	return bPacket, nil
 }
@@ -178,7 +295,7 @@ func (p *TBLSProxy) Unwrap(msg interface{}) (interface{}, *onet.OverlayMsg, erro
 	case TBLSOm:
 		return nil, bPacket.Om, nil
 	default:
-		panic("I'm freaking out")
+		return nil, nil, fmt.Errorf("tbls proxy: unknown packet type %d", bPacket.Type)
 	}
 	if err := decode(bPacket.Buff, ret, pairing.G1()); err != nil {
 		return nil, nil, err
@@ -202,12 +319,15 @@ type DKGAck struct{}
 
 var dkgAckType network.MessageTypeID
 
-func decode(buff []byte, packet interface{}, suite abstract.Suite) error {
-	cons := make(protobuf.Constructors)
-	var pt abstract.Point
-	var sc abstract.Scalar
-	cons[reflect.TypeOf(&pt).Elem()] = func() interface{} { return suite.Point() }
-	cons[reflect.TypeOf(&sc).Elem()] = func() interface{} { return suite.Scalar() }
-	return protobuf.DecodeWithConstructors(buff, packet, cons)
+// encode returns the binary representation of the packet using the encoding
+// of the given suite, in an envelope recording the curve and the group.
+func encode(packet interface{}, suite abstract.Suite) ([]byte, error) {
+	return pbc.Seal(suite, packet)
+}
 
+// decode reads the packet from its binary representation. Points and scalars
+// are instantiated by the suite, and envelopes sealed with another curve or
+// group are rejected.
+func decode(buff []byte, packet interface{}, suite abstract.Suite) error {
+	return pbc.OpenAs(suite, buff, packet)
 }
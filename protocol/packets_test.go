@@ -1,42 +1,86 @@
 package protocol
 
 import (
-	"reflect"
+	"encoding/json"
 	"testing"
 
+	"github.com/dedis/paper_17_dfinity/bls"
 	"github.com/dedis/paper_17_dfinity/pbc"
-	"github.com/dedis/protobuf"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/crypto.v0/random"
 )
 
 func TestMarshalling(t *testing.T) {
-	p := pbc.NewPairingFp382_2()
-	g2 := p.G2()
-	msg := &PBCContext{
-		Index:   0,
-		Private: g2.NewKey(random.Stream),
+	for _, curve := range []int{pbc.CurveFp382_2, pbc.CurveFp254BNb} {
+		if !pbc.Supported(curve) {
+			continue
+		}
+		g2 := pbc.NewPairing(curve).G2()
+		msg := &PBCContext{
+			Index:   0,
+			Private: g2.NewKey(random.Stream),
+		}
+		msg.Roster = []abstract.Point{g2.Point().Mul(nil, msg.Private)}
+		msg.Proofs = [][]byte{bls.PopProve(pbc.NewPairing(curve), msg.Private)}
+		buff, err := encode(msg, g2)
+		require.Nil(t, err)
+		decoded := &PBCContext{}
+		require.Nil(t, decode(buff, decoded, g2))
+		require.Equal(t, msg.Index, decoded.Index)
+		require.True(t, msg.Private.Equal(decoded.Private))
+		require.True(t, msg.Roster[0].Equal(decoded.Roster[0]))
+		require.Equal(t, msg.Proofs, decoded.Proofs)
+
+		// the envelope is rejected by the other groups
+		require.NotNil(t, decode(buff, &PBCContext{}, pbc.NewPairing(curve).G1()))
 	}
-	msg.Roster = []abstract.Point{g2.Point().Mul(nil, msg.Private)}
-	buff, err := protobuf.Encode(msg)
-	require.Nil(t, err)
-	decoded := &PBCContext{}
-	require.Nil(t, decode(buff, decoded, g2))
-	reflect.DeepEqual(decoded, msg)
-
-	p = pbc.NewPairingFp254BNb()
-	g2 = p.G2()
-	msg = &PBCContext{
-		Index:   0,
-		Private: g2.NewKey(random.Stream),
+}
+
+func TestPBCContextText(t *testing.T) {
+	for _, curve := range []int{pbc.CurveFp254BNb, pbc.CurveBLS12_381} {
+		if !pbc.Supported(curve) {
+			continue
+		}
+		p := pbc.NewPairing(curve)
+		g2 := p.G2()
+		c := &PBCContext{
+			Index:     1,
+			Private:   g2.NewKey(random.Stream),
+			Threshold: 2,
+		}
+		c.Roster = []abstract.Point{g2.Point().Base(), g2.Point().Mul(nil, c.Private)}
+		c.Proofs = [][]byte{bls.PopProve(p, g2.Scalar().One()), bls.PopProve(p, c.Private)}
+
+		check := func(d *PBCContext) {
+			require.Equal(t, c.Index, d.Index)
+			require.Equal(t, c.Threshold, d.Threshold)
+			require.True(t, c.Private.Equal(d.Private))
+			require.Len(t, d.Roster, len(c.Roster))
+			for i := range c.Roster {
+				require.True(t, c.Roster[i].Equal(d.Roster[i]))
+			}
+			require.Equal(t, c.Proofs, d.Proofs)
+		}
+
+		buff, err := json.Marshal(c)
+		require.Nil(t, err)
+		decoded := &PBCContext{}
+		require.Nil(t, json.Unmarshal(buff, decoded))
+		check(decoded)
+
+		text, err := c.MarshalText()
+		require.Nil(t, err)
+		decoded = &PBCContext{}
+		require.Nil(t, decoded.UnmarshalText(text))
+		check(decoded)
+
+		// every public key comes with its proof
+		require.Error(t, decoded.UnmarshalText(append(text, " 00"...)))
+		c.Proofs = c.Proofs[:1]
+		_, err = c.MarshalText()
+		require.Error(t, err)
 	}
-	msg.Roster = []abstract.Point{g2.Point().Mul(nil, msg.Private)}
-	buff, err = protobuf.Encode(msg)
-	require.Nil(t, err)
-	decoded = &PBCContext{}
-	require.Nil(t, decode(buff, decoded, g2))
-	reflect.DeepEqual(decoded, msg)
 }
 
 /*func TestMarshallingPoint(t *testing.T) {*/
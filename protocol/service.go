@@ -9,7 +9,6 @@ import (
 	"github.com/dedis/paper_17_dfinity/bls"
 	"github.com/dedis/paper_17_dfinity/pbc"
 	"github.com/dedis/paper_17_dfinity/pedersen/dkg"
-	"github.com/dedis/protobuf"
 	"gopkg.in/dedis/crypto.v0/abstract"
 	"gopkg.in/dedis/onet.v1"
 	"gopkg.in/dedis/onet.v1/network"
@@ -24,7 +23,7 @@ func init() {
 type Service struct {
 	c            *onet.Context
 	Context      *PBCContext
-	construct    protobuf.Constructors
+	registry     *bls.KeyRegistry // public keys of the context with their proofs checked
 	pairing      *pbc.Pairing
 	ackd         int
 	notify       chan bool
@@ -37,12 +36,11 @@ type Service struct {
 
 func NewService(c *onet.Context) onet.Service {
 	s := &Service{
-		c:         c,
-		construct: make(protobuf.Constructors),
-		notify:    make(chan bool),
-		Context:   new(PBCContext),
-		dksCond:   sync.NewCond(&sync.Mutex{}),
-		dkgWg:     new(sync.WaitGroup),
+		c:       c,
+		notify:  make(chan bool),
+		Context: new(PBCContext),
+		dksCond: sync.NewCond(&sync.Mutex{}),
+		dkgWg:   new(sync.WaitGroup),
 	}
 	c.RegisterProcessor(s, pbcrawType)
 	c.RegisterProcessor(s, pbcAck)
@@ -93,9 +91,13 @@ func (s *Service) RunTBLS(msg []byte) ([]byte, error) {
 	tree := s.onetRoster.GenerateNaryTreeWithRoot(n-1, s.c.ServerIdentity())
 	tni := s.c.NewTreeNodeInstance(tree, tree.Root, TBLSProtoName)
 
-	done := make(chan []byte)
-	callback := func(sig []byte) {
-		done <- sig
+	type result struct {
+		sig []byte
+		err error
+	}
+	done := make(chan result, 1)
+	callback := func(sig []byte, err error) {
+		done <- result{sig, err}
 	}
 
 	proto, err := NewTBLSRootProtocol(tni, s.dks, callback, msg)
@@ -108,9 +110,12 @@ func (s *Service) RunTBLS(msg []byte) ([]byte, error) {
 	go proto.Start()
 
 	select {
-	case sig := <-done:
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
 		log.Lvl1("Root Service TBLS DONE !")
-		return sig, bls.Verify(pairing, s.dks.Polynomial().Commit(), msg, sig)
+		return res.sig, bls.Verify(pairing, ciphersuite, s.dks.Polynomial().Commit(), msg, res.sig)
 	case <-time.After(10 * time.Minute):
 		return nil, errors.New("service root timeout on DKG")
 	}
@@ -148,11 +153,17 @@ func (s *Service) WaitDKGFinished() error {
 // curve is which curve of pbc are we using
 // roster is the list of public keys
 // private sis the list of private keys
+// The proofs of possession of the keys are sent along, so every node checks
+// them before accepting multisignatures of the roster.
 func (s *Service) BroadcastPBCContext(r *onet.Roster, Roster []abstract.Point, privates []abstract.Scalar, threshold int) {
 	// XXX constant pairing
 	//s.pairing = pbc.NewPairing(curve)
 	s.onetRoster = r
 	s.pairing = pairing
+	proofs := make([][]byte, len(privates))
+	for i, private := range privates {
+		proofs[i] = bls.PopProve(s.pairing, private)
+	}
 	own := s.c.ServerIdentity()
 	for i, si := range r.List {
 		c := &PBCContext{
@@ -160,10 +171,13 @@ func (s *Service) BroadcastPBCContext(r *onet.Roster, Roster []abstract.Point, p
 			Roster:    Roster,
 			Private:   privates[i],
 			Threshold: threshold,
+			Proofs:    proofs,
 		}
 
 		if own.Equal(si) {
-			s.setupContext(c)
+			if err := s.setupContext(c, r); err != nil {
+				panic(err)
+			}
 			continue
 		}
 		/*for i, r := range Roster {*/
@@ -172,12 +186,12 @@ func (s *Service) BroadcastPBCContext(r *onet.Roster, Roster []abstract.Point, p
 		//buff, _ := r.MarshalBinary()
 		//fmt.Printf("%x\n", buff)
 		/*}*/
-		buff, err := protobuf.Encode(c)
+		buff, err := encode(c, s.pairing.G2())
 		if err != nil {
 			panic(err)
 		}
 		log.Lvl1("DKG Service sending to ", i, "/", len(r.List))
-		if err := s.c.SendRaw(si, &PBCRaw{Context: buff}); err != nil {
+		if err := s.c.SendRaw(si, &PBCRaw{Context: buff, Roster: r}); err != nil {
 			log.Lvl1(err)
 			panic(err)
 		}
@@ -197,9 +211,13 @@ func (s *Service) Process(p *network.Envelope) {
 		g2 := s.pairing.G2()
 		context := new(PBCContext)
 		if err := decode(msg.Context, context, g2); err != nil {
-			panic(err)
+			log.Error("invalid context from", p.ServerIdentity, ":", err)
+			return
+		}
+		if err := s.setupContext(context, msg.Roster); err != nil {
+			log.Error("invalid context from", p.ServerIdentity, ":", err)
+			return
 		}
-		s.setupContext(context)
 		s.c.SendRaw(p.ServerIdentity, &PBCContextACK{s.Context.Index})
 	case *PBCContextACK:
 		s.ackd++
@@ -231,8 +249,32 @@ func (s *Service) waitDKGConfirmation() {
 	s.dksCond.L.Unlock()
 }
 
-func (s *Service) setupContext(c *PBCContext) {
+// setupContext registers the public keys of the roster of c, in its order,
+// once their proofs of possession are checked, and makes c the context of the
+// service. The i-th key of c belongs to the node r.List[i]: r is bound to the
+// context so multisignatures are only mapped to nodes through it.
+func (s *Service) setupContext(c *PBCContext, r *onet.Roster) error {
+	if r == nil || len(r.List) != len(c.Roster) {
+		return errors.New("protocol: roster does not match the context")
+	}
+	if c.Index < 0 || c.Index >= len(r.List) || !r.List[c.Index].Equal(s.c.ServerIdentity()) {
+		return errors.New("protocol: context of another node")
+	}
+	if len(c.Proofs) != len(c.Roster) {
+		return errors.New("protocol: roster without proofs of possession")
+	}
+	registry := bls.NewKeyRegistry(s.pairing)
+	for i, public := range c.Roster {
+		if err := registry.Register(public, c.Proofs[i]); err != nil {
+			return err
+		}
+	}
+	if registry.Len() != len(c.Roster) {
+		return errors.New("protocol: duplicated public key in the roster")
+	}
 	s.Context = c
+	s.registry = registry
+	s.onetRoster = r
 	s.c.ProtocolRegister(DKGProtoName, func(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
 		log.Fatal("ahahah")
 		return nil, nil
@@ -241,6 +283,7 @@ func (s *Service) setupContext(c *PBCContext) {
 		log.Fatal("ahahah")
 		return nil, nil
 	})
+	return nil
 }
 
 func (s *Service) NewProtocol(node *onet.TreeNodeInstance, c *onet.GenericConfig) (onet.ProtocolInstance, error) {
@@ -3,10 +3,12 @@ package protocol
 import (
 	"testing"
 
+	"github.com/dedis/paper_17_dfinity/bls"
 	"github.com/stretchr/testify/require"
 
 	"gopkg.in/dedis/onet.v1"
 	"gopkg.in/dedis/onet.v1/log"
+	"gopkg.in/dedis/onet.v1/network"
 )
 
 func TestService(t *testing.T) {
@@ -32,3 +34,51 @@ func TestService(t *testing.T) {
 	_, err := rootService.RunTBLS(msg)
 	require.Nil(t, err)
 }
+
+func TestServiceMultiSig(t *testing.T) {
+	n := 4
+	msg := []byte("crypto is good for your health")
+	local := onet.NewTCPTest()
+	hosts, roster, _ := local.GenTree(n, false)
+	defer local.CloseAll()
+
+	privs, pubs := GenerateBatchKeys(n)
+	rootService := hosts[0].GetService(ServiceName).(*Service)
+	rootService.BroadcastPBCContext(roster, pubs, privs, n/2+1)
+
+	sigs := make(map[int][]byte)
+	for _, h := range hosts[1:] {
+		i, sig := h.GetService(ServiceName).(*Service).MultiSign(msg)
+		require.Equal(t, h.ServerIdentity, roster.List[i])
+		sigs[i] = sig
+	}
+	m, err := bls.AggregateMultiSig(pairing, n, sigs)
+	require.Nil(t, err)
+	signers, err := rootService.VerifyMultiSig(roster, msg, m, n-1)
+	require.Nil(t, err)
+	require.Equal(t, roster.List[1:], signers)
+	_, err = rootService.VerifyMultiSig(roster, msg, m, n)
+	require.Equal(t, bls.ErrNotEnoughSigners, err)
+
+	// the signers can only be mapped through the roster of the context
+	list := append([]*network.ServerIdentity{roster.List[1], roster.List[0]}, roster.List[2:]...)
+	_, err = rootService.VerifyMultiSig(onet.NewRoster(list), msg, m, n-1)
+	require.Error(t, err)
+	_, err = rootService.VerifyMultiSig(onet.NewRoster(roster.List[:n-1]), msg, m, n-1)
+	require.Error(t, err)
+	_, err = rootService.VerifyMultiSig(nil, msg, m, n-1)
+	require.Error(t, err)
+
+	// a roster whose proofs of possession do not match its keys is refused
+	c := *rootService.Context
+	c.Proofs = append([][]byte{c.Proofs[1], c.Proofs[0]}, c.Proofs[2:]...)
+	require.Error(t, rootService.setupContext(&c, roster))
+	c.Proofs = nil
+	require.Error(t, rootService.setupContext(&c, roster))
+
+	// so is a context bound to another roster or meant for another node
+	c = *rootService.Context
+	require.Error(t, rootService.setupContext(&c, onet.NewRoster(list)))
+	require.Error(t, rootService.setupContext(&c, onet.NewRoster(roster.List[:n-1])))
+	require.Error(t, rootService.setupContext(&c, nil))
+}
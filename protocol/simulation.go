@@ -15,7 +15,8 @@ func init() {
 
 type Simulation struct {
 	onet.SimulationBFTree
-	Threshold  int // if 0, then threshold = n / 2 + 1
+	Threshold  int    // if 0, then threshold = n / 2 + 1
+	Curve      string // name of the pbc curve, Fp254BNb if empty
 	PBCRoster  []abstract.Point
 	PBCPrivate []abstract.Scalar
 }
@@ -24,6 +25,9 @@ func NewSimulation(config string) (onet.Simulation, error) {
 	s := &Simulation{}
 	_, err := toml.Decode(config, s)
 	// panics if something's wrong
+	if err == nil && s.Curve != "" {
+		err = SetCurve(s.Curve)
+	}
 	return s, err
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dedis/onet/log"
 	"github.com/dedis/paper_17_dfinity/bls"
+	"github.com/dedis/paper_17_dfinity/pbc"
 	"github.com/dedis/paper_17_dfinity/pedersen/dkg"
 	"gopkg.in/dedis/onet.v1"
 	"gopkg.in/dedis/onet.v1/network"
@@ -34,14 +35,28 @@ type OnSignature struct {
 
 type TBLSProto struct {
 	*onet.TreeNodeInstance
-	dks  *dkg.DistKeyShare
-	sigs []*bls.ThresholdSig
-	cb   func(sig []byte)
-	msg  []byte
-	done bool
+	dks      *dkg.DistKeyShare
+	sigs     []*bls.ThresholdSig
+	blames   []*bls.Blame
+	received int
+	cb       func(sig []byte, err error)
+	msg      []byte
+	done     bool
 	sync.Mutex
 }
 
+// TBLSError is reported to the callback of the root when the signatures of
+// all the nodes arrived but the signature could still not be recovered.
+// Blames lists the signatures rejected along the way.
+type TBLSError struct {
+	Err    error
+	Blames []*bls.Blame
+}
+
+func (e *TBLSError) Error() string {
+	return fmt.Sprintf("tbls: %v (%d signatures rejected)", e.Err, len(e.Blames))
+}
+
 func NewTBLSProtocol(tni *onet.TreeNodeInstance, dks *dkg.DistKeyShare) (onet.ProtocolInstance, error) {
 	t := &TBLSProto{
 		TreeNodeInstance: tni,
@@ -51,7 +66,9 @@ func NewTBLSProtocol(tni *onet.TreeNodeInstance, dks *dkg.DistKeyShare) (onet.Pr
 	return t, nil
 }
 
-func NewTBLSRootProtocol(tni *onet.TreeNodeInstance, dks *dkg.DistKeyShare, cb func(sig []byte), msg []byte) (onet.ProtocolInstance, error) {
+// NewTBLSRootProtocol returns the protocol of the root, which calls cb once
+// with either the recovered signature or a *TBLSError.
+func NewTBLSRootProtocol(tni *onet.TreeNodeInstance, dks *dkg.DistKeyShare, cb func(sig []byte, err error), msg []byte) (onet.ProtocolInstance, error) {
 	pi, _ := NewTBLSProtocol(tni, dks)
 	proto := pi.(*TBLSProto)
 	proto.cb = cb
@@ -60,20 +77,27 @@ func NewTBLSRootProtocol(tni *onet.TreeNodeInstance, dks *dkg.DistKeyShare, cb f
 }
 
 func (t *TBLSProto) Start() error {
-	ts := bls.ThresholdSign(pairing, t.dks, t.msg)
-	if !bls.ThresholdVerify(pairing, t.dks.Polynomial(), t.msg, ts) {
+	ts := bls.ThresholdSign(pairing, ciphersuite, t.dks, t.msg)
+	if !bls.ThresholdVerify(pairing, ciphersuite, t.dks.Polynomial(), t.msg, ts) {
 		panic("aaaa")
 	}
 
+	t.Lock()
 	t.sigs = append(t.sigs, ts)
+	t.received++
+	t.Unlock()
 	err := t.Broadcast(&TBLSRequest{t.msg})
 	log.LLvl2(t.Name(), " broadcasted TBLS request (err", err, ")")
 	return err
 }
 
+// OnRequest sends the partial signature of the node to the root and wipes it
+// once it is sent. The share of the node is not wiped: it is the key of the
+// following rounds, until the DistKeyShare is destroyed.
 func (t *TBLSProto) OnRequest(or OnRequest) error {
 	msg := or.TBLSRequest.Message
-	ts := bls.ThresholdSign(pairing, t.dks, msg)
+	ts := bls.ThresholdSign(pairing, ciphersuite, t.dks, msg)
+	defer pbc.Release(pairing.G1(), ts.Sig)
 
 	return t.SendToParent(ts)
 }
@@ -84,20 +108,72 @@ func (t *TBLSProto) OnSignature(os OnSignature) error {
 	if t.done {
 		return nil
 	}
-	if !bls.ThresholdVerify(pairing, t.dks.Polynomial(), t.msg, &os.ThresholdSig) {
-		panic(fmt.Errorf("%s: gave invalid signature", os.TreeNode.ServerIdentity.Address))
-	}
+	// the signatures are only checked if their recombination is invalid
 	t.sigs = append(t.sigs, &os.ThresholdSig)
+	t.received++
 	n := len(t.Roster().List)
 	threshold := t.dks.Polynomial().Threshold()
+	err := bls.ErrNotEnoughShares
 	if len(t.sigs) > threshold {
-		sig, err := bls.AggregateSignatures(pairing, t.dks.Polynomial(), t.msg, t.sigs, n, threshold)
-		if err != nil {
-			panic(err)
+		var sig []byte
+		var blames []*bls.Blame
+		sig, blames, err = bls.RobustAggregateSignatures(pairing, ciphersuite, t.dks.Polynomial(), t.msg, t.sigs, n, threshold)
+		t.drop(blames)
+		if err == nil {
+			t.done = true
+			t.releaseSigs()
+			t.cb(sig, nil)
+			return nil
 		}
+	}
+	if t.received < n {
+		// wait for more signatures
+		return nil
+	}
+	// no other signature will come: report the failure instead of waiting
+	t.done = true
+	for _, sig := range t.sigs {
+		pbc.Release(pairing.G1(), sig.Sig)
+	}
+	t.sigs = nil
+	tErr := &TBLSError{Err: err, Blames: t.blames}
+	t.blames = nil
+	t.cb(nil, tErr)
+	return tErr
+}
 
-		t.done = true
-		t.cb(sig)
+// releaseSigs wipes the partial signatures, the rejected ones included, once
+// the signature is recovered.
+func (t *TBLSProto) releaseSigs() {
+	for _, sig := range t.sigs {
+		pbc.Release(pairing.G1(), sig.Sig)
+	}
+	t.sigs = nil
+	for _, b := range t.blames {
+		if b.Sig != nil {
+			pbc.Release(pairing.G1(), b.Sig.Sig)
+		}
+	}
+	t.blames = nil
+}
+
+// drop moves the blamed signatures from t.sigs to t.blames, which keeps them
+// for the report of a failure.
+func (t *TBLSProto) drop(blames []*bls.Blame) {
+	if len(blames) == 0 {
+		return
+	}
+	blamed := make(map[*bls.ThresholdSig]bool, len(blames))
+	for _, b := range blames {
+		log.Error(t.Name(), ": share", b.Index, "rejected:", b.Reason)
+		blamed[b.Sig] = true
+	}
+	t.blames = append(t.blames, blames...)
+	valid := t.sigs[:0]
+	for _, sig := range t.sigs {
+		if !blamed[sig] {
+			valid = append(valid, sig)
+		}
 	}
-	return nil
+	t.sigs = valid
 }
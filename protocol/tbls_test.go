@@ -75,13 +75,13 @@ func TestTBLS(test *testing.T) {
 		msg := []byte("Hello World")
 		sigs := make([]*bls.ThresholdSig, nbrHosts)
 		for i, d := range dkss {
-			sigs[i] = bls.ThresholdSign(pairing, d, msg)
+			sigs[i] = bls.ThresholdSign(pairing, ciphersuite, d, msg)
 			fmt.Printf("TBLS sig[%d] -> (%d) %s\n", i, sigs[i].Index, sigs[i].Sig.String())
 		}
 		poly := dkss[0].Polynomial()
-		sig, err := bls.AggregateSignatures(pairing, poly, msg, sigs, nbrHosts, t)
+		sig, err := bls.AggregateSignatures(pairing, ciphersuite, poly, msg, sigs, nbrHosts, t)
 		require.Nil(test, err)
-		require.Nil(test, bls.Verify(pairing, poly.Commit(), msg, sig))
+		require.Nil(test, bls.Verify(pairing, ciphersuite, poly.Commit(), msg, sig))
 
 		fmt.Println(" ---------- network test -----------")
 		for i := range rand.Perm(len(dkss)) {
@@ -100,7 +100,8 @@ func TestTBLS(test *testing.T) {
 		}
 
 		sigDone := make(chan []byte)
-		sigCb := func(sig []byte) {
+		sigCb := func(sig []byte, err error) {
+			require.NoError(test, err)
 			sigDone <- sig
 		}
 		hosts[0].ProtocolRegister(TBLSProtoName, func(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
@@ -117,7 +118,7 @@ func TestTBLS(test *testing.T) {
 
 		select {
 		case sig := <-sigDone:
-			require.NoError(test, bls.Verify(pairing, dkss[0].Polynomial().Commit(), msg, sig))
+			require.NoError(test, bls.Verify(pairing, ciphersuite, dkss[0].Polynomial().Commit(), msg, sig))
 		case <-time.After(5 * time.Second):
 			test.Fatal("hello")
 		}